@@ -0,0 +1,131 @@
+package dynago
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/twharmon/slices"
+)
+
+// timestamps are the auto timestamps of an item being written. They
+// are set on a copy of the item, which is what gets marshalled, and
+// only set on the item itself by commit once the write succeeds.
+type timestamps struct {
+	// item is the copy of the item with the timestamps set, and val
+	// its struct value.
+	item interface{}
+	val  reflect.Value
+
+	orig   reflect.Value
+	now    time.Time
+	fields []*field
+}
+
+// autoTimestamps returns the auto timestamps of the item, set to the
+// current time. Fields tagged create are only set if put is true and
+// they are zero. Fields tagged update are always set.
+func (d *Dynago) autoTimestamps(item Keyer, put bool) (*timestamps, error) {
+	ty, val := tyVal(item)
+	c, err := d.codec(ty)
+	if err != nil {
+		return nil, fmt.Errorf("d.codec: %w", err)
+	}
+	ts := timestamps{item: item, val: val, orig: val}
+	if len(c.auto) == 0 {
+		return &ts, nil
+	}
+	cp := reflect.New(ty)
+	cp.Elem().Set(val)
+	ts.item, ts.val = cp.Interface(), cp.Elem()
+	ts.now = d.config.Now()
+	for _, f := range c.auto {
+		if f.auto == "create" {
+			if !put {
+				continue
+			}
+			fv := f.value(val, false)
+			for fv.IsValid() && fv.Kind() == reflect.Pointer && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.IsValid() && fv.Kind() != reflect.Pointer && !fv.Interface().(time.Time).IsZero() {
+				continue
+			}
+		}
+		if setTime(ts.val, f.index, ts.now, true) {
+			ts.fields = append(ts.fields, f)
+		}
+	}
+	return &ts, nil
+}
+
+// update returns the fields tagged update.
+func (ts *timestamps) update() []*field {
+	return slices.Filter(ts.fields, func(f *field) bool {
+		return f.auto == "update"
+	})
+}
+
+// skip removes the field from the timestamps, so that commit does not
+// set it on the item.
+func (ts *timestamps) skip(f *field) {
+	for i := range ts.fields {
+		if ts.fields[i] == f {
+			ts.fields = append(ts.fields[:i:i], ts.fields[i+1:]...)
+			return
+		}
+	}
+}
+
+// commit sets the timestamps on the item. It is called once the write
+// succeeds. Nothing is done if the item was not passed by pointer.
+func (ts *timestamps) commit() {
+	if !ts.orig.CanSet() {
+		return
+	}
+	for _, f := range ts.fields {
+		setTime(ts.orig, f.index, ts.now, false)
+	}
+}
+
+// setTime sets the field at index of the struct value v to t,
+// allocating nil pointers on the way. If clone is true, pointers that
+// are not nil are replaced by pointers to copies, so that values
+// shared with another struct are not changed. It reports whether the
+// field was set.
+func setTime(v reflect.Value, index []int, t time.Time, clone bool) bool {
+	for i, x := range index {
+		if i > 0 {
+			var ok bool
+			if v, ok = elem(v, clone); !ok {
+				return false
+			}
+		}
+		v = v.Field(x)
+	}
+	v, ok := elem(v, clone)
+	if !ok || !v.CanSet() {
+		return false
+	}
+	v.Set(reflect.ValueOf(t))
+	return true
+}
+
+// elem dereferences v, allocating nil pointers, or copying the values
+// pointed to if clone is true.
+func elem(v reflect.Value, clone bool) (reflect.Value, bool) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() || clone {
+			if !v.CanSet() {
+				return v, false
+			}
+			p := reflect.New(v.Type().Elem())
+			if !v.IsNil() {
+				p.Elem().Set(v.Elem())
+			}
+			v.Set(p)
+		}
+		v = v.Elem()
+	}
+	return v, true
+}
@@ -0,0 +1,209 @@
+package dynago_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/twharmon/dynago"
+)
+
+type AutoPost struct {
+	*SimpleTable
+	ID      string    `attr:"PK" fmt:"Post#{}"`
+	Created time.Time `auto:"create" layout:"2006-01-02"`
+	Updated time.Time `auto:"update" copy:"GSISK"`
+}
+
+func TestAutoPutItem(t *testing.T) {
+	ddb := mock(t)
+	now := time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC)
+	client := dynago.New(ddb, &dynago.Config{
+		DefaultTableName: "foo",
+		Now:              func() time.Time { return now },
+	})
+	p := AutoPost{ID: "foo"}
+	ddb.MockPut(&dynamodb.PutItemInput{
+		TableName: aws.String("foo"),
+		Item: map[string]*dynamodb.AttributeValue{
+			"PK":      {S: aws.String("Post#foo")},
+			"Created": {S: aws.String("2023-04-05")},
+			"Updated": {S: aws.String("2023-04-05T06:07:08Z")},
+			"GSISK":   {S: aws.String("2023-04-05T06:07:08Z")},
+		},
+	})
+	if err := client.PutItem(&p).Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, now, p.Created)
+	assertEq(t, now, p.Updated)
+	ddb.done()
+}
+
+func TestAutoPutItemCreatedSet(t *testing.T) {
+	ddb := mock(t)
+	now := time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC)
+	created := time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC)
+	client := dynago.New(ddb, &dynago.Config{
+		DefaultTableName: "foo",
+		Now:              func() time.Time { return now },
+	})
+	p := AutoPost{ID: "foo", Created: created}
+	ddb.MockPut(&dynamodb.PutItemInput{
+		TableName: aws.String("foo"),
+		Item: map[string]*dynamodb.AttributeValue{
+			"PK":      {S: aws.String("Post#foo")},
+			"Created": {S: aws.String("2022-01-02")},
+			"Updated": {S: aws.String("2023-04-05T06:07:08Z")},
+			"GSISK":   {S: aws.String("2023-04-05T06:07:08Z")},
+		},
+	})
+	// Items passed by value are not changed.
+	if err := client.PutItem(p).Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, time.Time{}, p.Updated)
+	ddb.done()
+}
+
+func TestAutoUpdateItem(t *testing.T) {
+	ddb := mock(t)
+	now := time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC)
+	client := dynago.New(ddb, &dynago.Config{
+		DefaultTableName: "foo",
+		Now:              func() time.Time { return now },
+	})
+	p := AutoPost{ID: "foo"}
+	ddb.MockUpdate(&dynamodb.UpdateItemInput{
+		TableName: aws.String("foo"),
+		Key: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Post#foo")},
+		},
+		UpdateExpression: aws.String("SET #a0 = :a0, #a1 = :a0, #u0 = :u0"),
+		ExpressionAttributeNames: map[string]*string{
+			"#u0": aws.String("Title"),
+			"#a0": aws.String("Updated"),
+			"#a1": aws.String("GSISK"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":u0": {S: aws.String("Hello")},
+			":a0": {S: aws.String("2023-04-05T06:07:08Z")},
+		},
+	})
+	if err := client.UpdateItem(&p).Set("Title", "Hello").Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, time.Time{}, p.Created)
+	assertEq(t, now, p.Updated)
+	ddb.done()
+}
+
+func TestAutoTagNotTime(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Post struct {
+		*SimpleTable
+		ID      string `attr:"PK" fmt:"Post#{}"`
+		Created string `auto:"create"`
+	}
+	if err := client.PutItem(&Post{ID: "foo"}).Exec(); err == nil {
+		t.Fatalf("want err")
+	}
+	ddb.done()
+}
+
+func TestAutoUpdateItemSet(t *testing.T) {
+	ddb := mock(t)
+	now := time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC)
+	updated := time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC)
+	client := dynago.New(ddb, &dynago.Config{
+		DefaultTableName: "foo",
+		Now:              func() time.Time { return now },
+	})
+	p := AutoPost{ID: "foo"}
+	ddb.MockUpdate(&dynamodb.UpdateItemInput{
+		TableName: aws.String("foo"),
+		Key: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Post#foo")},
+		},
+		UpdateExpression: aws.String("SET #u0 = :u0"),
+		ExpressionAttributeNames: map[string]*string{
+			"#u0": aws.String("Updated"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":u0": {S: aws.String("2022-01-02T00:00:00Z")},
+		},
+	})
+	if err := client.UpdateItem(&p).Set("Updated", updated).Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, time.Time{}, p.Updated)
+	ddb.done()
+}
+
+func TestAutoUpdateItemDiff(t *testing.T) {
+	ddb := mock(t)
+	now := time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC)
+	client := dynago.New(ddb, &dynago.Config{
+		DefaultTableName: "foo",
+		Now:              func() time.Time { return now },
+	})
+	before := AutoPost{ID: "foo"}
+	after := AutoPost{ID: "foo", Updated: time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC)}
+	ddb.MockUpdate(&dynamodb.UpdateItemInput{
+		TableName: aws.String("foo"),
+		Key: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Post#foo")},
+		},
+		UpdateExpression: aws.String("SET #u0 = :u0, #u1 = :u1"),
+		ExpressionAttributeNames: map[string]*string{
+			"#u0": aws.String("GSISK"),
+			"#u1": aws.String("Updated"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":u0": {S: aws.String("2022-01-02T00:00:00Z")},
+			":u1": {S: aws.String("2022-01-02T00:00:00Z")},
+		},
+	})
+	if err := client.UpdateItem(&after).Diff(&before).Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC), after.Updated)
+	ddb.done()
+}
+
+func TestAutoPutItemErr(t *testing.T) {
+	ddb := mock(t)
+	now := time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC)
+	client := dynago.New(ddb, &dynago.Config{
+		DefaultTableName: "foo",
+		Now:              func() time.Time { return now },
+	})
+	type Meta struct {
+		Updated *time.Time `auto:"update"`
+	}
+	type Post struct {
+		*SimpleTable
+		*Meta
+		ID string `attr:"PK" fmt:"Post#{}"`
+	}
+	updated := time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC)
+	p := Post{ID: "foo", Meta: &Meta{Updated: &updated}}
+	ddb.MockPutError(&dynamodb.PutItemInput{
+		TableName: aws.String("foo"),
+		Item: map[string]*dynamodb.AttributeValue{
+			"PK":      {S: aws.String("Post#foo")},
+			"Updated": {S: aws.String("2023-04-05T06:07:08Z")},
+		},
+	}, errors.New("failed"))
+	if err := client.PutItem(&p).Exec(); err == nil {
+		t.Fatalf("want err")
+	}
+	// The item, and values it shares with others, are only changed
+	// once the write succeeds.
+	assertEq(t, time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC), updated)
+	assertEq(t, &updated, p.Updated)
+	ddb.done()
+}
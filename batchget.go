@@ -0,0 +1,188 @@
+package dynago
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// ErrUnprocessed is returned when a batch operation still has
+// unprocessed keys or items after all retries.
+var ErrUnprocessed = errors.New("dynago: unprocessed keys or items remain after retries")
+
+// maxBatchGetKeys is the maximum number of keys DynamoDB accepts in a
+// single BatchGetItem request.
+const maxBatchGetKeys = 100
+
+// BatchGetItem represents a BatchGetItem operation.
+type BatchGetItem struct {
+	tables         []string
+	items          map[string][]Keyer
+	consistentRead *bool
+	output         *BatchGetItemOutput
+	dynago         *Dynago
+}
+
+// BatchGetItemOutput represents the output of a BatchGetItem
+// operation.
+type BatchGetItemOutput struct {
+	// NotFound holds the items that were not found.
+	NotFound []Keyer
+
+	// Unprocessed holds the items that were still unprocessed after
+	// all retries.
+	Unprocessed []Keyer
+}
+
+// BatchGetItem returns a BatchGetItem operation. The given items are
+// read from the default table and must have the primary key fields
+// set.
+func (d *Dynago) BatchGetItem(items ...Keyer) *BatchGetItem {
+	q := &BatchGetItem{
+		items:          make(map[string][]Keyer),
+		consistentRead: &d.config.DefaultConsistentRead,
+		dynago:         d,
+	}
+	return q.TableItems(d.config.DefaultTableName, items...)
+}
+
+// TableItems adds items to be read from the given table.
+func (q *BatchGetItem) TableItems(table string, items ...Keyer) *BatchGetItem {
+	if len(items) == 0 {
+		return q
+	}
+	if _, ok := q.items[table]; !ok {
+		q.tables = append(q.tables, table)
+	}
+	q.items[table] = append(q.items[table], items...)
+	return q
+}
+
+// ConsistentRead sets ConsistentRead for every table.
+func (q *BatchGetItem) ConsistentRead(consistent bool) *BatchGetItem {
+	q.consistentRead = &consistent
+	return q
+}
+
+// Output sets the output to be populated when the operation is
+// executed.
+func (q *BatchGetItem) Output(output *BatchGetItemOutput) *BatchGetItem {
+	q.output = output
+	return q
+}
+
+type batchGetKey struct {
+	table string
+	key   map[string]*dynamodb.AttributeValue
+}
+
+// Exec executes the operation. Items that are found are unmarshalled
+// into the structs that were given. ErrUnprocessed is returned if
+// some keys could not be processed after all retries.
+func (q *BatchGetItem) Exec() error {
+	return q.ExecWithContext(context.Background())
+}
+
+// ExecWithContext executes the operation with the given context.
+func (q *BatchGetItem) ExecWithContext(ctx context.Context) error {
+	var all []Keyer
+	var keys []batchGetKey
+	pending := make(map[string]map[string][]int)
+	keyNames := make(map[string]map[string][]string)
+	for _, table := range q.tables {
+		pending[table] = make(map[string][]int)
+		keyNames[table] = make(map[string][]string)
+		for _, item := range q.items[table] {
+			key, err := q.dynago.key(item)
+			if err != nil {
+				return fmt.Errorf("q.dynago.key: %w", err)
+			}
+			ks := keyString(key)
+			if _, ok := pending[table][ks]; !ok {
+				keys = append(keys, batchGetKey{table: table, key: key})
+				names := make([]string, 0, len(key))
+				for name := range key {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				keyNames[table][strings.Join(names, ",")] = names
+			}
+			pending[table][ks] = append(pending[table][ks], len(all))
+			all = append(all, item)
+		}
+	}
+	found := make([]bool, len(all))
+	unprocessed := make([]bool, len(all))
+	anyUnprocessed := false
+	for start := 0; start < len(keys); start += maxBatchGetKeys {
+		end := start + maxBatchGetKeys
+		if end > len(keys) {
+			end = len(keys)
+		}
+		input := &dynamodb.BatchGetItemInput{
+			RequestItems: make(map[string]*dynamodb.KeysAndAttributes),
+		}
+		for _, k := range keys[start:end] {
+			if input.RequestItems[k.table] == nil {
+				input.RequestItems[k.table] = &dynamodb.KeysAndAttributes{
+					ConsistentRead: q.consistentRead,
+				}
+			}
+			input.RequestItems[k.table].Keys = append(input.RequestItems[k.table].Keys, k.key)
+		}
+		for attempt := 0; len(input.RequestItems) > 0; attempt++ {
+			if attempt > 0 {
+				if attempt > q.dynago.config.MaxBatchRetries {
+					for table, ka := range input.RequestItems {
+						for _, key := range ka.Keys {
+							for _, i := range pending[table][keyString(key)] {
+								unprocessed[i] = true
+								anyUnprocessed = true
+							}
+						}
+					}
+					break
+				}
+				if err := batchBackoff(ctx, attempt); err != nil {
+					return err
+				}
+			}
+			output, err := q.dynago.ddb.BatchGetItemWithContext(ctx, input)
+			if err != nil {
+				return fmt.Errorf("d.ddb.BatchGetItem: %w", mapError(err))
+			}
+			for table, items := range output.Responses {
+				for _, av := range items {
+					for _, names := range keyNames[table] {
+						for _, i := range pending[table][keyString(pick(av, names))] {
+							if err := q.dynago.Unmarshal(av, all[i]); err != nil {
+								return fmt.Errorf("q.dynago.Unmarshal: %w", err)
+							}
+							found[i] = true
+						}
+					}
+				}
+			}
+			input = &dynamodb.BatchGetItemInput{RequestItems: output.UnprocessedKeys}
+		}
+	}
+	if q.output != nil {
+		q.output.NotFound = nil
+		q.output.Unprocessed = nil
+		for i, item := range all {
+			if unprocessed[i] {
+				q.output.Unprocessed = append(q.output.Unprocessed, item)
+			} else if !found[i] {
+				q.output.NotFound = append(q.output.NotFound, item)
+			}
+		}
+	}
+	if anyUnprocessed {
+		return ErrUnprocessed
+	}
+	return nil
+}
@@ -0,0 +1,290 @@
+package dynago_test
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/twharmon/dynago"
+)
+
+func TestBatchGetItemBasic(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64
+	}
+	want := []Person{{Name: "foo", Age: 33}, {Name: "bar", Age: 34}}
+	ddb.MockBatchGet(&dynamodb.BatchGetItemInput{
+		RequestItems: map[string]*dynamodb.KeysAndAttributes{
+			"foo": {
+				ConsistentRead: aws.Bool(false),
+				Keys: []map[string]*dynamodb.AttributeValue{
+					{"PK": {S: aws.String("Person#foo")}},
+					{"PK": {S: aws.String("Person#bar")}},
+				},
+			},
+		},
+	}, &dynamodb.BatchGetItemOutput{
+		Responses: map[string][]map[string]*dynamodb.AttributeValue{
+			"foo": {
+				{"PK": {S: aws.String("Person#bar")}, "Age": {N: aws.String("34")}},
+				{"PK": {S: aws.String("Person#foo")}, "Age": {N: aws.String("33")}},
+			},
+		},
+	})
+	got := []Person{{Name: "foo"}, {Name: "bar"}}
+	if err := client.BatchGetItem(&got[0], &got[1]).Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, want, got)
+	ddb.done()
+}
+
+func TestBatchGetItemMultipleTables(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64
+	}
+	type Post struct {
+		*CompositeTable
+		Author string `attr:"PK" fmt:"Author#{}"`
+		ID     int64  `attr:"SK" fmt:"Post#{}"`
+		Title  string
+	}
+	ddb.MockBatchGet(&dynamodb.BatchGetItemInput{
+		RequestItems: map[string]*dynamodb.KeysAndAttributes{
+			"people": {
+				ConsistentRead: aws.Bool(true),
+				Keys: []map[string]*dynamodb.AttributeValue{
+					{"PK": {S: aws.String("Person#foo")}},
+				},
+			},
+			"posts": {
+				ConsistentRead: aws.Bool(true),
+				Keys: []map[string]*dynamodb.AttributeValue{
+					{"PK": {S: aws.String("Author#foo")}, "SK": {S: aws.String("Post#1")}},
+				},
+			},
+		},
+	}, &dynamodb.BatchGetItemOutput{
+		Responses: map[string][]map[string]*dynamodb.AttributeValue{
+			"people": {
+				{"PK": {S: aws.String("Person#foo")}, "Age": {N: aws.String("33")}},
+			},
+			"posts": {
+				{"PK": {S: aws.String("Author#foo")}, "SK": {S: aws.String("Post#1")}, "Title": {S: aws.String("bar")}},
+			},
+		},
+	})
+	person := Person{Name: "foo"}
+	post := Post{Author: "foo", ID: 1}
+	if err := client.BatchGetItem().
+		TableItems("people", &person).
+		TableItems("posts", &post).
+		ConsistentRead(true).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, Person{Name: "foo", Age: 33}, person)
+	assertEq(t, Post{Author: "foo", ID: 1, Title: "bar"}, post)
+	ddb.done()
+}
+
+func TestBatchGetItemNotFound(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64
+	}
+	ddb.MockBatchGet(&dynamodb.BatchGetItemInput{
+		RequestItems: map[string]*dynamodb.KeysAndAttributes{
+			"foo": {
+				ConsistentRead: aws.Bool(false),
+				Keys: []map[string]*dynamodb.AttributeValue{
+					{"PK": {S: aws.String("Person#foo")}},
+					{"PK": {S: aws.String("Person#bar")}},
+				},
+			},
+		},
+	}, &dynamodb.BatchGetItemOutput{
+		Responses: map[string][]map[string]*dynamodb.AttributeValue{
+			"foo": {
+				{"PK": {S: aws.String("Person#foo")}, "Age": {N: aws.String("33")}},
+			},
+		},
+	})
+	foo := Person{Name: "foo"}
+	bar := Person{Name: "bar"}
+	var output dynago.BatchGetItemOutput
+	if err := client.BatchGetItem(&foo, &bar).Output(&output).Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, []dynago.Keyer{&bar}, output.NotFound)
+	assertEq(t, Person{Name: "foo", Age: 33}, foo)
+	ddb.done()
+}
+
+func TestBatchGetItemDuplicateKeys(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64
+	}
+	ddb.MockBatchGet(&dynamodb.BatchGetItemInput{
+		RequestItems: map[string]*dynamodb.KeysAndAttributes{
+			"foo": {
+				ConsistentRead: aws.Bool(false),
+				Keys: []map[string]*dynamodb.AttributeValue{
+					{"PK": {S: aws.String("Person#foo")}},
+				},
+			},
+		},
+	}, &dynamodb.BatchGetItemOutput{
+		Responses: map[string][]map[string]*dynamodb.AttributeValue{
+			"foo": {
+				{"PK": {S: aws.String("Person#foo")}, "Age": {N: aws.String("33")}},
+			},
+		},
+	})
+	got := []Person{{Name: "foo"}, {Name: "foo"}}
+	if err := client.BatchGetItem(&got[0], &got[1]).Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, []Person{{Name: "foo", Age: 33}, {Name: "foo", Age: 33}}, got)
+	ddb.done()
+}
+
+func TestBatchGetItemChunks(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64
+	}
+	var people []Person
+	var items []dynago.Keyer
+	for i := 0; i < 150; i++ {
+		people = append(people, Person{Name: strconv.Itoa(i)})
+	}
+	for i := range people {
+		items = append(items, &people[i])
+	}
+	for start := 0; start < len(people); start += 100 {
+		keys := []map[string]*dynamodb.AttributeValue{}
+		responses := []map[string]*dynamodb.AttributeValue{}
+		for i := start; i < start+100 && i < len(people); i++ {
+			pk := fmt.Sprintf("Person#%d", i)
+			keys = append(keys, map[string]*dynamodb.AttributeValue{"PK": {S: aws.String(pk)}})
+			responses = append(responses, map[string]*dynamodb.AttributeValue{"PK": {S: aws.String(pk)}, "Age": {N: aws.String(strconv.Itoa(i))}})
+		}
+		ddb.MockBatchGet(&dynamodb.BatchGetItemInput{
+			RequestItems: map[string]*dynamodb.KeysAndAttributes{
+				"foo": {ConsistentRead: aws.Bool(false), Keys: keys},
+			},
+		}, &dynamodb.BatchGetItemOutput{
+			Responses: map[string][]map[string]*dynamodb.AttributeValue{"foo": responses},
+		})
+	}
+	if err := client.BatchGetItem(items...).Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	for i := range people {
+		assertEq(t, int64(i), people[i].Age)
+	}
+	ddb.done()
+}
+
+func TestBatchGetItemUnprocessedKeys(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64
+	}
+	unprocessed := map[string]*dynamodb.KeysAndAttributes{
+		"foo": {
+			ConsistentRead: aws.Bool(false),
+			Keys: []map[string]*dynamodb.AttributeValue{
+				{"PK": {S: aws.String("Person#bar")}},
+			},
+		},
+	}
+	ddb.MockBatchGet(&dynamodb.BatchGetItemInput{
+		RequestItems: map[string]*dynamodb.KeysAndAttributes{
+			"foo": {
+				ConsistentRead: aws.Bool(false),
+				Keys: []map[string]*dynamodb.AttributeValue{
+					{"PK": {S: aws.String("Person#foo")}},
+					{"PK": {S: aws.String("Person#bar")}},
+				},
+			},
+		},
+	}, &dynamodb.BatchGetItemOutput{
+		Responses: map[string][]map[string]*dynamodb.AttributeValue{
+			"foo": {
+				{"PK": {S: aws.String("Person#foo")}, "Age": {N: aws.String("33")}},
+			},
+		},
+		UnprocessedKeys: unprocessed,
+	})
+	ddb.MockBatchGet(&dynamodb.BatchGetItemInput{
+		RequestItems: unprocessed,
+	}, &dynamodb.BatchGetItemOutput{
+		Responses: map[string][]map[string]*dynamodb.AttributeValue{
+			"foo": {
+				{"PK": {S: aws.String("Person#bar")}, "Age": {N: aws.String("34")}},
+			},
+		},
+	})
+	got := []Person{{Name: "foo"}, {Name: "bar"}}
+	if err := client.BatchGetItem(&got[0], &got[1]).Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, []Person{{Name: "foo", Age: 33}, {Name: "bar", Age: 34}}, got)
+	ddb.done()
+}
+
+func TestBatchGetItemUnprocessedKeysExhausted(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo", MaxBatchRetries: -1})
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64
+	}
+	input := &dynamodb.BatchGetItemInput{
+		RequestItems: map[string]*dynamodb.KeysAndAttributes{
+			"foo": {
+				ConsistentRead: aws.Bool(false),
+				Keys: []map[string]*dynamodb.AttributeValue{
+					{"PK": {S: aws.String("Person#foo")}},
+				},
+			},
+		},
+	}
+	ddb.MockBatchGet(input, &dynamodb.BatchGetItemOutput{UnprocessedKeys: input.RequestItems})
+	got := Person{Name: "foo"}
+	var output dynago.BatchGetItemOutput
+	err := client.BatchGetItem(&got).Output(&output).Exec()
+	if !errors.Is(err, dynago.ErrUnprocessed) {
+		t.Fatalf("want ErrUnprocessed; got %v", err)
+	}
+	assertEq(t, []dynago.Keyer{&got}, output.Unprocessed)
+	assertEq(t, []dynago.Keyer(nil), output.NotFound)
+	ddb.done()
+}
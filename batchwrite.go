@@ -0,0 +1,232 @@
+package dynago
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// maxBatchWriteItems is the maximum number of items DynamoDB accepts
+// in a single BatchWriteItem request.
+const maxBatchWriteItems = 25
+
+// BatchWriteItem represents a BatchWriteItem operation.
+type BatchWriteItem struct {
+	writes []*batchWrite
+	dynago *Dynago
+}
+
+type batchWrite struct {
+	table  string
+	item   Keyer
+	delete bool
+	key    map[string]*dynamodb.AttributeValue
+	req    *dynamodb.WriteRequest
+}
+
+// BatchWriteError is returned when some items of a BatchWriteItem
+// operation could not be written.
+type BatchWriteError struct {
+	Failures []*BatchWriteFailure
+}
+
+// BatchWriteFailure describes an item that could not be written.
+type BatchWriteFailure struct {
+	// TableName is the table the item was written to.
+	TableName string
+
+	// Item is the item that was put or deleted.
+	Item Keyer
+
+	// Delete is true if the item was to be deleted.
+	Delete bool
+
+	// Err is the reason the item could not be written. It is
+	// ErrUnprocessed if the item was still unprocessed after all
+	// retries.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *BatchWriteError) Error() string {
+	return fmt.Sprintf("dynago: %d batch write items failed: %s", len(e.Failures), e.Failures[0].Err)
+}
+
+// Is reports whether any of the failures matches the target.
+func (e *BatchWriteError) Is(target error) bool {
+	for _, f := range e.Failures {
+		if errors.Is(f.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// BatchWriteItem returns a BatchWriteItem operation.
+func (d *Dynago) BatchWriteItem() *BatchWriteItem {
+	return &BatchWriteItem{dynago: d}
+}
+
+// Put adds items to be put in the default table. If a key is put or
+// deleted more than once, the last write wins, and the earlier ones
+// are dropped without being reported.
+func (q *BatchWriteItem) Put(items ...Keyer) *BatchWriteItem {
+	return q.TablePut(q.dynago.config.DefaultTableName, items...)
+}
+
+// Delete adds items to be deleted from the default table. The items
+// must have the primary key fields set. If a key is put or deleted
+// more than once, the last write wins, and the earlier ones are
+// dropped without being reported.
+func (q *BatchWriteItem) Delete(items ...Keyer) *BatchWriteItem {
+	return q.TableDelete(q.dynago.config.DefaultTableName, items...)
+}
+
+// TablePut adds items to be put in the given table. The last write
+// of a key wins, as with Put.
+func (q *BatchWriteItem) TablePut(table string, items ...Keyer) *BatchWriteItem {
+	for _, item := range items {
+		q.writes = append(q.writes, &batchWrite{table: table, item: item})
+	}
+	return q
+}
+
+// TableDelete adds items to be deleted from the given table. The
+// items must have the primary key fields set. The last write of a key
+// wins, as with Delete.
+func (q *BatchWriteItem) TableDelete(table string, items ...Keyer) *BatchWriteItem {
+	for _, item := range items {
+		q.writes = append(q.writes, &batchWrite{table: table, item: item, delete: true})
+	}
+	return q
+}
+
+// Exec executes the operation. Items are written in requests of up
+// to 25 items, and unprocessed items are retried with exponential
+// backoff. Only the last write of each key is made. A
+// *BatchWriteError is returned if some items could not be written.
+func (q *BatchWriteItem) Exec() error {
+	return q.ExecWithContext(context.Background())
+}
+
+// ExecWithContext executes the operation with the given context.
+func (q *BatchWriteItem) ExecWithContext(ctx context.Context) error {
+	// DynamoDB rejects requests that write the same key more than
+	// once, so only the last write of each key is sent.
+	var writes []*batchWrite
+	pending := make(map[string]map[string]int)
+	keyNames := make(map[string]map[string][]string)
+	for _, w := range q.writes {
+		var err error
+		w.key, err = q.dynago.key(w.item)
+		if err != nil {
+			return fmt.Errorf("q.dynago.key: %w", err)
+		}
+		if w.delete {
+			w.req = &dynamodb.WriteRequest{DeleteRequest: &dynamodb.DeleteRequest{Key: w.key}}
+		} else {
+			item, err := q.dynago.Marshal(w.item)
+			if err != nil {
+				return fmt.Errorf("q.dynago.Marshal: %w", err)
+			}
+			w.req = &dynamodb.WriteRequest{PutRequest: &dynamodb.PutRequest{Item: item}}
+		}
+		if pending[w.table] == nil {
+			pending[w.table] = make(map[string]int)
+			keyNames[w.table] = make(map[string][]string)
+		}
+		ks := keyString(w.key)
+		if i, ok := pending[w.table][ks]; ok {
+			writes[i] = w
+		} else {
+			pending[w.table][ks] = len(writes)
+			writes = append(writes, w)
+		}
+		names := make([]string, 0, len(w.key))
+		for name := range w.key {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		keyNames[w.table][strings.Join(names, ",")] = names
+	}
+	failed := make(map[*batchWrite]error)
+	fail := func(table string, req *dynamodb.WriteRequest, err error) {
+		var av map[string]*dynamodb.AttributeValue
+		if req.PutRequest != nil {
+			av = req.PutRequest.Item
+		} else if req.DeleteRequest != nil {
+			av = req.DeleteRequest.Key
+		}
+		for _, names := range keyNames[table] {
+			if i, ok := pending[table][keyString(pick(av, names))]; ok {
+				failed[writes[i]] = err
+			}
+		}
+	}
+	for start := 0; start < len(writes); start += maxBatchWriteItems {
+		end := start + maxBatchWriteItems
+		if end > len(writes) {
+			end = len(writes)
+		}
+		input := &dynamodb.BatchWriteItemInput{
+			RequestItems: make(map[string][]*dynamodb.WriteRequest),
+		}
+		for _, w := range writes[start:end] {
+			if err := ctx.Err(); err != nil {
+				failed[w] = err
+				continue
+			}
+			input.RequestItems[w.table] = append(input.RequestItems[w.table], w.req)
+		}
+		for attempt := 0; len(input.RequestItems) > 0; attempt++ {
+			if attempt > 0 {
+				if attempt > q.dynago.config.MaxBatchRetries {
+					for table, reqs := range input.RequestItems {
+						for _, req := range reqs {
+							fail(table, req, ErrUnprocessed)
+						}
+					}
+					break
+				}
+				if err := batchBackoff(ctx, attempt); err != nil {
+					for table, reqs := range input.RequestItems {
+						for _, req := range reqs {
+							fail(table, req, err)
+						}
+					}
+					break
+				}
+			}
+			output, err := q.dynago.ddb.BatchWriteItemWithContext(ctx, input)
+			if err != nil {
+				err = fmt.Errorf("d.ddb.BatchWriteItem: %w", mapError(err))
+				for table, reqs := range input.RequestItems {
+					for _, req := range reqs {
+						fail(table, req, err)
+					}
+				}
+				break
+			}
+			input = &dynamodb.BatchWriteItemInput{RequestItems: output.UnprocessedItems}
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	var failures []*BatchWriteFailure
+	for _, w := range writes {
+		if err, ok := failed[w]; ok {
+			failures = append(failures, &BatchWriteFailure{
+				TableName: w.table,
+				Item:      w.item,
+				Delete:    w.delete,
+				Err:       err,
+			})
+		}
+	}
+	return &BatchWriteError{Failures: failures}
+}
@@ -0,0 +1,240 @@
+package dynago_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/twharmon/dynago"
+)
+
+func TestBatchWriteItemBasic(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64
+	}
+	foo := Person{Name: "foo", Age: 33}
+	bar := Person{Name: "bar", Age: 34}
+	ddb.MockBatchWrite(&dynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]*dynamodb.WriteRequest{
+			"foo": {
+				{PutRequest: &dynamodb.PutRequest{Item: map[string]*dynamodb.AttributeValue{
+					"PK":  {S: aws.String("Person#foo")},
+					"Age": {N: aws.String("33")},
+				}}},
+				{DeleteRequest: &dynamodb.DeleteRequest{Key: map[string]*dynamodb.AttributeValue{
+					"PK": {S: aws.String("Person#bar")},
+				}}},
+			},
+		},
+	}, &dynamodb.BatchWriteItemOutput{})
+	if err := client.BatchWriteItem().Put(&foo).Delete(&bar).Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	ddb.done()
+}
+
+func TestBatchWriteItemDuplicateKeys(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64
+	}
+	foo := Person{Name: "foo", Age: 33}
+	foo2 := Person{Name: "foo", Age: 34}
+	bar := Person{Name: "bar", Age: 35}
+	ddb.MockBatchWrite(&dynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]*dynamodb.WriteRequest{
+			"foo": {
+				{PutRequest: &dynamodb.PutRequest{Item: map[string]*dynamodb.AttributeValue{
+					"PK":  {S: aws.String("Person#foo")},
+					"Age": {N: aws.String("34")},
+				}}},
+				{DeleteRequest: &dynamodb.DeleteRequest{Key: map[string]*dynamodb.AttributeValue{
+					"PK": {S: aws.String("Person#bar")},
+				}}},
+			},
+		},
+	}, &dynamodb.BatchWriteItemOutput{})
+	if err := client.BatchWriteItem().
+		Put(&foo, &foo, &bar, &foo2).
+		Delete(&bar).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	ddb.done()
+}
+
+func TestBatchWriteItemMultipleTables(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+	}
+	foo := Person{Name: "foo"}
+	bar := Person{Name: "bar"}
+	ddb.MockBatchWrite(&dynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]*dynamodb.WriteRequest{
+			"people": {
+				{PutRequest: &dynamodb.PutRequest{Item: map[string]*dynamodb.AttributeValue{
+					"PK": {S: aws.String("Person#foo")},
+				}}},
+			},
+			"archive": {
+				{DeleteRequest: &dynamodb.DeleteRequest{Key: map[string]*dynamodb.AttributeValue{
+					"PK": {S: aws.String("Person#bar")},
+				}}},
+			},
+		},
+	}, &dynamodb.BatchWriteItemOutput{})
+	if err := client.BatchWriteItem().
+		TablePut("people", &foo).
+		TableDelete("archive", &bar).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	ddb.done()
+}
+
+func TestBatchWriteItemChunks(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+	}
+	var items []dynago.Keyer
+	for i := 0; i < 60; i++ {
+		items = append(items, &Person{Name: strconv.Itoa(i)})
+	}
+	for start := 0; start < len(items); start += 25 {
+		var reqs []*dynamodb.WriteRequest
+		for i := start; i < start+25 && i < len(items); i++ {
+			reqs = append(reqs, &dynamodb.WriteRequest{PutRequest: &dynamodb.PutRequest{Item: map[string]*dynamodb.AttributeValue{
+				"PK": {S: aws.String(fmt.Sprintf("Person#%d", i))},
+			}}})
+		}
+		ddb.MockBatchWrite(&dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]*dynamodb.WriteRequest{"foo": reqs},
+		}, &dynamodb.BatchWriteItemOutput{})
+	}
+	if err := client.BatchWriteItem().Put(items...).Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	ddb.done()
+}
+
+func TestBatchWriteItemUnprocessedItems(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+	}
+	foo := Person{Name: "foo"}
+	bar := Person{Name: "bar"}
+	unprocessed := map[string][]*dynamodb.WriteRequest{
+		"foo": {
+			{DeleteRequest: &dynamodb.DeleteRequest{Key: map[string]*dynamodb.AttributeValue{
+				"PK": {S: aws.String("Person#bar")},
+			}}},
+		},
+	}
+	ddb.MockBatchWrite(&dynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]*dynamodb.WriteRequest{
+			"foo": {
+				{PutRequest: &dynamodb.PutRequest{Item: map[string]*dynamodb.AttributeValue{
+					"PK": {S: aws.String("Person#foo")},
+				}}},
+				unprocessed["foo"][0],
+			},
+		},
+	}, &dynamodb.BatchWriteItemOutput{UnprocessedItems: unprocessed})
+	ddb.MockBatchWrite(&dynamodb.BatchWriteItemInput{RequestItems: unprocessed}, &dynamodb.BatchWriteItemOutput{})
+	if err := client.BatchWriteItem().Put(&foo).Delete(&bar).Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	ddb.done()
+}
+
+func TestBatchWriteItemFailures(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo", MaxBatchRetries: -1})
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+	}
+	var items []dynago.Keyer
+	for i := 0; i < 26; i++ {
+		items = append(items, &Person{Name: strconv.Itoa(i)})
+	}
+	var reqs []*dynamodb.WriteRequest
+	for i := 0; i < 25; i++ {
+		reqs = append(reqs, &dynamodb.WriteRequest{PutRequest: &dynamodb.PutRequest{Item: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String(fmt.Sprintf("Person#%d", i))},
+		}}})
+	}
+	ddb.MockBatchWrite(&dynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]*dynamodb.WriteRequest{"foo": reqs},
+	}, &dynamodb.BatchWriteItemOutput{
+		UnprocessedItems: map[string][]*dynamodb.WriteRequest{"foo": reqs[3:4]},
+	})
+	reqErr := errors.New("foo")
+	ddb.MockBatchWrite(&dynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]*dynamodb.WriteRequest{
+			"foo": {{PutRequest: &dynamodb.PutRequest{Item: map[string]*dynamodb.AttributeValue{
+				"PK": {S: aws.String("Person#25")},
+			}}}},
+		},
+	}, nil, reqErr)
+	err := client.BatchWriteItem().Put(items...).Exec()
+	var bwErr *dynago.BatchWriteError
+	if !errors.As(err, &bwErr) {
+		t.Fatalf("want *BatchWriteError; got %v", err)
+	}
+	assertEq(t, 2, len(bwErr.Failures))
+	assertEq(t, items[3], bwErr.Failures[0].Item)
+	assertEq(t, "foo", bwErr.Failures[0].TableName)
+	assertEq(t, dynago.ErrUnprocessed, bwErr.Failures[0].Err)
+	assertEq(t, items[25], bwErr.Failures[1].Item)
+	if !errors.Is(bwErr.Failures[1].Err, reqErr) {
+		t.Fatalf("want %v; got %v", reqErr, bwErr.Failures[1].Err)
+	}
+	if !errors.Is(err, dynago.ErrUnprocessed) {
+		t.Fatalf("want errors.Is ErrUnprocessed")
+	}
+	ddb.done()
+}
+
+func TestBatchWriteItemExecWithContextCanceled(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+	}
+	foo := Person{Name: "foo"}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := client.BatchWriteItem().Put(&foo).ExecWithContext(ctx)
+	var bwErr *dynago.BatchWriteError
+	if !errors.As(err, &bwErr) {
+		t.Fatalf("want *BatchWriteError; got %v", err)
+	}
+	assertEq(t, []*dynago.BatchWriteFailure{{
+		TableName: "foo",
+		Item:      &foo,
+		Err:       context.Canceled,
+	}}, bwErr.Failures)
+	ddb.done()
+}
@@ -6,8 +6,13 @@ import (
 	"github.com/twharmon/dynago"
 )
 
-// BenchmarkMarshall-10      	 1279446	       924.3 ns/op	     867 B/op	      10 allocs/op
-// BenchmarkUnmarshall-10    	 1500501	       801.1 ns/op	     254 B/op	       6 allocs/op
+// go test -run '^$' -bench . -benchmem, with GOMAXPROCS=1:
+//
+// BenchmarkMarshall         	  899946	      1118 ns/op	     632 B/op	       8 allocs/op
+// BenchmarkUnmarshall       	 4398435	       346.4 ns/op	      32 B/op	       1 allocs/op
+// BenchmarkMarshallFmt      	  494709	      2103 ns/op	     752 B/op	      16 allocs/op
+// BenchmarkUnmarshallFmt    	 1554644	      1004 ns/op	     104 B/op	       3 allocs/op
+// BenchmarkMarshallParallel 	  685702	      1655 ns/op	     648 B/op	       9 allocs/op
 
 func BenchmarkMarshall(b *testing.B) {
 	ddb := mock(b)
@@ -55,3 +60,77 @@ func BenchmarkUnmarshall(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkMarshallFmt(b *testing.B) {
+	ddb := mock(b)
+	client := dynago.New(ddb)
+
+	type Member struct {
+		*CompositeTable
+		OrgID  string `attr:"-"`
+		UserID string `attr:"PK" fmt:"Org#{OrgID}#User#{}"`
+		Seq    int64  `attr:"SK" fmt:"Seq#{:010d}"`
+	}
+	member := Member{
+		OrgID:  "a#b",
+		UserID: "Gopher",
+		Seq:    42,
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.Marshal(&member); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshallFmt(b *testing.B) {
+	ddb := mock(b)
+	client := dynago.New(ddb)
+
+	type Member struct {
+		*CompositeTable
+		OrgID  string `attr:"-"`
+		UserID string `attr:"PK" fmt:"Org#{OrgID}#User#{}"`
+		Seq    int64  `attr:"SK" fmt:"Seq#{:010d}"`
+	}
+	member := Member{
+		OrgID:  "a#b",
+		UserID: "Gopher",
+		Seq:    42,
+	}
+	av, err := client.Marshal(&member)
+	if err != nil {
+		b.Fatal(err)
+	}
+	var output Member
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := client.Unmarshal(av, &output); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMarshallParallel(b *testing.B) {
+	ddb := mock(b)
+	client := dynago.New(ddb)
+
+	type Person struct {
+		*CompositeTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64
+	}
+	person := Person{
+		Name: "Gopher",
+		Age:  14,
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := client.Marshal(&person); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,365 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"github.com/twharmon/dynago/internal/tags"
+)
+
+// errUnsupported is wrapped by errors of fields the generator can not
+// generate code for.
+var errUnsupported = errors.New("unsupported")
+
+// field is a field of a struct, resolved from its tags by the tags
+// package like the reflective path of dynago does.
+type field struct {
+	name        string
+	path        []*types.Var
+	index       []int
+	attrName    string
+	attrType    string
+	fmt         string
+	tmpl        *tags.Template
+	layout      string
+	attrsToCopy []string
+	omitEmpty   bool
+	null        bool
+	ttl         bool
+	ptr         bool
+	typ         types.Type
+	custom      bool
+
+	// refs holds the field of each placeholder of the template, or nil
+	// for literals.
+	refs []*field
+}
+
+// raw returns the type of the field, which may be a pointer.
+func (f *field) raw() types.Type {
+	return f.path[len(f.path)-1].Type()
+}
+
+// isTime reports whether t is time.Time.
+func isTime(t types.Type) bool {
+	n, ok := types.Unalias(t).(*types.Named)
+	return ok && n.Obj().Pkg() != nil && n.Obj().Pkg().Path() == "time" && n.Obj().Name() == "Time"
+}
+
+// deref returns the type t points to, and the number of pointers.
+func deref(t types.Type) (types.Type, int) {
+	n := 0
+	for {
+		p, ok := types.Unalias(t).(*types.Pointer)
+		if !ok {
+			return t, n
+		}
+		t = p.Elem()
+		n++
+	}
+}
+
+// kindOf returns the kind of the type t.
+func kindOf(t types.Type) tags.Kind {
+	if isTime(t) {
+		return tags.KindTime
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Kind() == types.Uintptr:
+			return tags.KindOther
+		case u.Info()&types.IsString != 0:
+			return tags.KindString
+		case u.Info()&types.IsBoolean != 0:
+			return tags.KindBool
+		case u.Info()&types.IsUnsigned != 0:
+			return tags.KindUint
+		case u.Info()&types.IsInteger != 0:
+			return tags.KindInt
+		case u.Info()&types.IsFloat != 0:
+			return tags.KindFloat
+		}
+	case *types.Slice:
+		if b, ok := u.Elem().Underlying().(*types.Basic); ok && b.Kind() == types.Uint8 {
+			return tags.KindBytes
+		}
+		return tags.KindSlice
+	case *types.Array:
+		return tags.KindArray
+	case *types.Map:
+		return tags.KindMap
+	case *types.Struct:
+		return tags.KindStruct
+	case *types.Interface:
+		return tags.KindInterface
+	}
+	return tags.KindOther
+}
+
+// bits returns the size of a number type as passed to strconv, which
+// is 0 for int and uint.
+func bits(t types.Type) int {
+	switch t.Underlying().(*types.Basic).Kind() {
+	case types.Int8, types.Uint8:
+		return 8
+	case types.Int16, types.Uint16:
+		return 16
+	case types.Int32, types.Uint32, types.Float32:
+		return 32
+	case types.Int64, types.Uint64, types.Float64:
+		return 64
+	}
+	return 0
+}
+
+// hasMethod reports whether the method set of t has the method.
+func hasMethod(t types.Type, name string) bool {
+	return types.NewMethodSet(t).Lookup(nil, name) != nil
+}
+
+// hasPtrMethod reports whether the method set of *t has the method.
+func hasPtrMethod(t types.Type, name string) bool {
+	return hasMethod(types.NewPointer(t), name)
+}
+
+// isCustom reports whether values of the type t, or of the type it
+// points to, marshal or unmarshal themselves.
+func isCustom(t types.Type) bool {
+	t, _ = deref(t)
+	if isTime(t) {
+		return false
+	}
+	for _, m := range []string{"MarshalAttribute", "UnmarshalAttribute", "MarshalText", "UnmarshalText"} {
+		if hasMethod(t, m) || hasPtrMethod(t, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// isText reports whether values of the type t are formatted with
+// their encoding.TextMarshaler implementation in fmt templates.
+func isText(t types.Type) bool {
+	return !isTime(t) && (hasMethod(t, "MarshalText") || hasPtrMethod(t, "MarshalText")) && hasPtrMethod(t, "UnmarshalText")
+}
+
+// tagType describes the type t of a field to the tags package.
+func tagType(t types.Type) tags.Type {
+	tt := tags.Type{Kind: kindOf(t), String: t.String(), Text: isText(t)}
+	switch u := t.Underlying().(type) {
+	case *types.Map:
+		tt.SetMap = isEmptyStruct(u.Elem())
+	case *types.Slice:
+		el, _ := deref(u.Elem())
+		tt.TimeElem = isTime(el)
+	}
+	return tt
+}
+
+// field resolves the field at the end of the path from its tags.
+func (g *generator) field(path []*types.Var, index []int, tag reflect.StructTag) (*field, *tags.Field, error) {
+	v := path[len(path)-1]
+	ty, ptrs := deref(v.Type())
+	tf, err := tags.Parse(v.Name(), index, v.Exported(), tag, tags.DefaultNames, tagType(ty), g.omitEmpty, g.null)
+	if err != nil {
+		return nil, nil, err
+	}
+	f := field{
+		name:        tf.Name,
+		path:        path,
+		index:       tf.Index,
+		attrName:    tf.AttrName,
+		attrType:    tf.AttrType,
+		fmt:         tf.Fmt,
+		tmpl:        tf.Template,
+		layout:      tf.Layout,
+		attrsToCopy: tf.AttrsToCopy,
+		omitEmpty:   tf.OmitEmpty,
+		null:        tf.Null,
+		ttl:         tf.TTL,
+	}
+	if v.Exported() {
+		f.typ = ty
+		f.ptr = ptrs > 0
+		f.custom = isCustom(ty)
+	}
+	return &f, tf, nil
+}
+
+// fields returns the fields of the struct type ty, promoting the fields
+// of embedded structs like the reflective path does.
+func (g *generator) fields(ty *types.Named) ([]*field, error) {
+	type embedded struct {
+		st    *types.Struct
+		path  []*types.Var
+		index []int
+	}
+	var fields []*field
+	var parsed []*tags.Field
+	var visited []*types.Struct
+	next := []embedded{{st: ty.Underlying().(*types.Struct)}}
+	for len(next) > 0 {
+		current := next
+		next = nil
+	outer:
+		for _, e := range current {
+			for _, st := range visited {
+				if types.Identical(st, e.st) {
+					continue outer
+				}
+			}
+			visited = append(visited, e.st)
+			for i := 0; i < e.st.NumFields(); i++ {
+				v := e.st.Field(i)
+				tag := reflect.StructTag(e.st.Tag(i))
+				path := append(append([]*types.Var(nil), e.path...), v)
+				index := append(append([]int(nil), e.index...), i)
+				name, _, _ := strings.Cut(tag.Get("attr"), ",")
+				if v.Embedded() {
+					ft, ptrs := deref(v.Type())
+					if !v.Exported() && ptrs > 0 {
+						continue
+					}
+					if st, ok := ft.Underlying().(*types.Struct); ok && name == "" && !isTime(ft) && !isCustom(ft) {
+						if ptrs > 1 {
+							return nil, fmt.Errorf("embedded field %s: %w", v.Name(), errUnsupported)
+						}
+						next = append(next, embedded{st: st, path: path, index: index})
+						continue
+					}
+				}
+				f, tf, err := g.field(path, index, tag)
+				if err != nil {
+					return nil, err
+				}
+				fields = append(fields, f)
+				parsed = append(parsed, tf)
+			}
+		}
+	}
+	typeOf := func(i int) tags.Type {
+		return tagType(fields[i].typ)
+	}
+	for i, f := range fields {
+		if f.attrName == "-" || f.attrType != "S" || (f.custom && f.fmt == "{}") {
+			continue
+		}
+		resolved, err := f.tmpl.Resolve(i, parsed, typeOf)
+		if err != nil {
+			return nil, err
+		}
+		f.refs = make([]*field, len(resolved))
+		for j, k := range resolved {
+			if k >= 0 {
+				f.refs[j] = fields[k]
+			}
+		}
+	}
+	var visible []*field
+	for _, i := range tags.Visible(parsed) {
+		if fields[i].attrName != "-" {
+			visible = append(visible, fields[i])
+		}
+	}
+	for _, f := range visible {
+		if err := supported(f); err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.name, err)
+		}
+	}
+	return visible, nil
+}
+
+// supported returns an error wrapping errUnsupported if no code can be
+// generated for the field.
+func supported(f *field) error {
+	if _, ptrs := deref(f.raw()); ptrs > 1 {
+		return fmt.Errorf("pointer to pointer: %w", errUnsupported)
+	}
+	switch {
+	case f.custom && f.fmt == "{}":
+		if !hasPtrMethod(f.typ, "MarshalAttribute") && !hasPtrMethod(f.typ, "MarshalText") {
+			return fmt.Errorf("type %s has no marshal method: %w", f.typ, errUnsupported)
+		}
+		if !hasPtrMethod(f.typ, "UnmarshalAttribute") && !hasPtrMethod(f.typ, "UnmarshalText") {
+			return fmt.Errorf("type %s has no unmarshal method: %w", f.typ, errUnsupported)
+		}
+		return nil
+	case f.attrType == "S":
+		for _, pf := range f.refs {
+			if pf == nil {
+				continue
+			}
+			if _, ptrs := deref(pf.raw()); ptrs > 1 {
+				return fmt.Errorf("pointer to pointer: %w", errUnsupported)
+			}
+		}
+		if f.fmt == "{}" {
+			return simple(f.typ)
+		}
+		return nil
+	case tags.IsSetType(f.attrType):
+		if kindOf(f.typ) != tags.KindSlice {
+			return fmt.Errorf("set of type %s: %w", f.typ, errUnsupported)
+		}
+		el := f.typ.Underlying().(*types.Slice).Elem()
+		if isCustom(el) {
+			return fmt.Errorf("set of type %s: %w", f.typ, errUnsupported)
+		}
+		switch k := kindOf(el); {
+		case f.attrType == "SS" && k == tags.KindString,
+			f.attrType == "NS" && (k == tags.KindInt || k == tags.KindUint || k == tags.KindFloat),
+			f.attrType == "BS" && k == tags.KindBytes:
+			return nil
+		}
+		return fmt.Errorf("%s set of type %s: %w", f.attrType, f.typ, errUnsupported)
+	}
+	return simple(f.typ)
+}
+
+// simple returns an error wrapping errUnsupported if values of the
+// type t are not marshalled by kind.
+func simple(t types.Type) error {
+	if isCustom(t) {
+		return fmt.Errorf("type %s: %w", t, errUnsupported)
+	}
+	switch kindOf(t) {
+	case tags.KindString, tags.KindBool, tags.KindInt, tags.KindUint, tags.KindFloat, tags.KindTime, tags.KindBytes:
+		return nil
+	case tags.KindSlice:
+		el := t.Underlying().(*types.Slice).Elem()
+		if _, ptrs := deref(el); ptrs > 0 || kindOf(el) == tags.KindSlice {
+			return fmt.Errorf("type %s: %w", t, errUnsupported)
+		}
+		return simple(el)
+	}
+	return fmt.Errorf("type %s: %w", t, errUnsupported)
+}
+
+// setType returns the set type of sets with elements of the type t,
+// or "" if they can not be in a set.
+func setType(t types.Type) string {
+	t, _ = deref(t)
+	switch kindOf(t) {
+	case tags.KindString:
+		return "SS"
+	case tags.KindInt, tags.KindUint, tags.KindFloat:
+		return "NS"
+	case tags.KindBytes:
+		return "BS"
+	}
+	return ""
+}
+
+func isEmptyStruct(t types.Type) bool {
+	st, ok := types.Unalias(t).(*types.Struct)
+	return ok && st.NumFields() == 0
+}
+
+func isSetMap(t types.Type) bool {
+	m, ok := t.Underlying().(*types.Map)
+	return ok && isEmptyStruct(m.Elem())
+}
@@ -0,0 +1,777 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/twharmon/dynago/internal/tags"
+)
+
+// generatedMethodRegExp matches the errors of type checking code that
+// references the generated methods before they exist.
+var generatedMethodRegExp = regexp.MustCompile(`\b(MarshalDynago|UnmarshalDynago|DynagoConfig)\b`)
+
+const (
+	dynagoPath   = "github.com/twharmon/dynago"
+	dynamodbPath = "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// generator generates the methods of the structs of a package.
+type generator struct {
+	types     []string
+	omitEmpty bool
+	null      bool
+	warn      func(format string, args ...interface{})
+
+	pkg     *types.Package
+	imports map[string]string
+	buf     bytes.Buffer
+	tmp     int
+}
+
+// generate type checks the package in dir, ignoring the file out, and
+// returns the source of the file with the generated methods.
+func (g *generator) generate(dir string, out string) ([]byte, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	out, err = filepath.Abs(out)
+	if err != nil {
+		return nil, err
+	}
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range bp.GoFiles {
+		path := filepath.Join(dir, name)
+		if path == out {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	var typeErr error
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error: func(err error) {
+			// The methods being generated may be referenced already.
+			if typeErr == nil && !generatedMethodRegExp.MatchString(err.Error()) {
+				typeErr = err
+			}
+		},
+	}
+	pkg, _ := conf.Check(bp.ImportPath, fset, files, nil)
+	if typeErr != nil {
+		return nil, typeErr
+	}
+	if pkg == nil {
+		return nil, fmt.Errorf("can not type check package in %s", dir)
+	}
+	g.pkg = pkg
+	g.imports = map[string]string{dynamodbPath: "dynamodb"}
+	g.buf.Reset()
+	var names []string
+	if len(g.types) > 0 {
+		names = g.types
+	} else {
+		for _, name := range pkg.Scope().Names() {
+			if g.isKeyer(pkg.Scope().Lookup(name)) {
+				names = append(names, name)
+			}
+		}
+	}
+	n := 0
+	for _, name := range names {
+		obj, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+		if !ok {
+			return nil, fmt.Errorf("type %s not found", name)
+		}
+		named, ok := obj.Type().(*types.Named)
+		if !ok || named.TypeParams() != nil {
+			return nil, fmt.Errorf("type %s must be a non-generic named struct", name)
+		}
+		if _, ok := named.Underlying().(*types.Struct); !ok {
+			return nil, fmt.Errorf("type %s must be a struct", name)
+		}
+		fields, err := g.fields(named)
+		if errors.Is(err, errUnsupported) {
+			g.warn("skipping %s: %s", name, err)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("type %s: %w", name, err)
+		}
+		g.marshal(name, fields)
+		g.unmarshal(name, fields)
+		g.config(name)
+		n++
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("no structs to generate methods for in %s", dir)
+	}
+	g.helpers()
+	return g.source(pkg.Name())
+}
+
+// isKeyer reports whether obj is a struct type implementing
+// dynago.Keyer.
+func (g *generator) isKeyer(obj types.Object) bool {
+	tn, ok := obj.(*types.TypeName)
+	if !ok || tn.IsAlias() {
+		return false
+	}
+	named, ok := tn.Type().(*types.Named)
+	if !ok || named.TypeParams() != nil {
+		return false
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return false
+	}
+	m, _, _ := types.LookupFieldOrMethod(types.NewPointer(named), true, g.pkg, "PrimaryKeys")
+	fn, ok := m.(*types.Func)
+	if !ok {
+		return false
+	}
+	sig := fn.Type().(*types.Signature)
+	if sig.Params().Len() != 0 || sig.Results().Len() != 1 {
+		return false
+	}
+	sl, ok := sig.Results().At(0).Type().(*types.Slice)
+	return ok && types.Identical(sl.Elem(), types.Typ[types.String])
+}
+
+// source returns the formatted source of the generated file.
+func (g *generator) source(pkgName string) ([]byte, error) {
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by dynago-gen. DO NOT EDIT.\n\npackage %s\n\nimport (\n", pkgName)
+	var std, other []string
+	for path := range g.imports {
+		if strings.Contains(path, ".") {
+			other = append(other, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+	for _, path := range std {
+		fmt.Fprintf(&src, "\t%q\n", path)
+	}
+	if len(std) > 0 && len(other) > 0 {
+		src.WriteString("\n")
+	}
+	for _, path := range other {
+		fmt.Fprintf(&src, "\t%q\n", path)
+	}
+	fmt.Fprintf(&src, ")\n")
+	src.Write(g.buf.Bytes())
+	out, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+	return out, nil
+}
+
+func (g *generator) p(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+	g.buf.WriteByte('\n')
+}
+
+// use marks the package as imported and returns its name.
+func (g *generator) use(path string) string {
+	name := path[strings.LastIndexByte(path, '/')+1:]
+	g.imports[path] = name
+	return name
+}
+
+// typ returns the type t as written in the generated file.
+func (g *generator) typ(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string {
+		if p == g.pkg {
+			return ""
+		}
+		g.imports[p.Path()] = p.Name()
+		return p.Name()
+	})
+}
+
+// close closes n blocks.
+func (g *generator) close(n int) {
+	for i := 0; i < n; i++ {
+		g.p("}")
+	}
+}
+
+// v returns a new variable name.
+func (g *generator) v(prefix string) string {
+	g.tmp++
+	return prefix + strconv.Itoa(g.tmp)
+}
+
+// marshal generates the MarshalDynago method of the struct.
+func (g *generator) marshal(name string, fields []*field) {
+	g.p("")
+	g.p("// MarshalDynago converts the %s into a DynamoDB item.", name)
+	g.p("func (x %s) MarshalDynago() (map[string]*dynamodb.AttributeValue, error) {", name)
+	g.p("item := make(map[string]*dynamodb.AttributeValue, %d)", len(fields))
+	for _, f := range fields {
+		g.p("{")
+		closing := g.guard(f, false)
+		g.p("var av *dynamodb.AttributeValue")
+		// Templates read the fields from x.
+		tmpl := f.attrType == "S" && !(f.custom && f.fmt == "{}") && !(f.fmt == "{}" && kindOf(f.typ) == tags.KindString)
+		if f.ptr || f.omitEmpty || !tmpl {
+			g.p("fv := x.%s", f.selector())
+		}
+		if f.omitEmpty {
+			g.p("if !(%s) {", g.isEmpty("fv", f.raw()))
+		}
+		if f.ptr {
+			g.p("if fv != nil {")
+			if !tmpl {
+				g.p("v := *fv")
+			}
+		} else if !tmpl {
+			g.p("v := fv")
+		}
+		g.encodeField(f)
+		if f.ptr {
+			if f.null {
+				g.p("} else {")
+				g.p("av = &dynamodb.AttributeValue{NULL: dynagoBool(true)}")
+			}
+			g.p("}")
+		}
+		if f.omitEmpty {
+			g.p("}")
+		}
+		g.p("if av != nil {")
+		g.p("item[%q] = av", f.attrName)
+		for _, cp := range f.attrsToCopy {
+			g.p("item[%q] = av", cp)
+		}
+		g.p("}")
+		g.close(closing)
+		g.p("}")
+	}
+	g.p("return item, nil")
+	g.p("}")
+}
+
+// unmarshal generates the UnmarshalDynago method of the struct.
+func (g *generator) unmarshal(name string, fields []*field) {
+	g.p("")
+	g.p("// UnmarshalDynago converts a DynamoDB item into the %s.", name)
+	g.p("func (x *%s) UnmarshalDynago(item map[string]*dynamodb.AttributeValue) error {", name)
+	for _, f := range fields {
+		g.p("if av := item[%q]; av != nil {", f.attrName)
+		g.guard(f, true)
+		g.p("if av.NULL != nil && *av.NULL {")
+		g.p("var zero %s", g.typ(f.raw()))
+		g.p("x.%s = zero", f.selector())
+		g.p("} else {")
+		target := "x." + f.selector()
+		if f.ptr {
+			g.p("if %s == nil {", target)
+			g.p("%s = new(%s)", target, g.typ(f.typ))
+			g.p("}")
+			target = "(*" + target + ")"
+		}
+		g.decodeField(f, target)
+		g.p("}")
+		g.p("}")
+	}
+	g.p("return nil")
+	g.p("}")
+}
+
+// selector returns the selector of the field from the struct.
+func (f *field) selector() string {
+	names := make([]string, len(f.path))
+	for i, v := range f.path {
+		names[i] = v.Name()
+	}
+	return strings.Join(names, ".")
+}
+
+// guard generates the checks of the nil embedded struct pointers the
+// field is promoted through. If alloc is true, they are allocated.
+// Otherwise, a block is opened for each, and their number is returned.
+func (g *generator) guard(f *field, alloc bool) int {
+	n := 0
+	sel := "x"
+	for _, v := range f.path[:len(f.path)-1] {
+		sel += "." + v.Name()
+		ty, ptrs := deref(v.Type())
+		if ptrs == 0 {
+			continue
+		}
+		if alloc {
+			g.p("if %s == nil {", sel)
+			g.p("%s = new(%s)", sel, g.typ(ty))
+			g.p("}")
+		} else {
+			g.p("if %s != nil {", sel)
+			n++
+		}
+	}
+	return n
+}
+
+// isEmpty returns the condition of the value v of the type t being
+// empty.
+func (g *generator) isEmpty(v string, t types.Type) string {
+	if _, ptrs := deref(t); ptrs > 0 {
+		return v + " == nil"
+	}
+	switch kindOf(t) {
+	case tags.KindString, tags.KindBytes, tags.KindSlice, tags.KindMap, tags.KindArray:
+		return "len(" + v + ") == 0"
+	case tags.KindBool:
+		return "!bool(" + v + ")"
+	case tags.KindInt, tags.KindUint, tags.KindFloat:
+		return v + " == 0"
+	case tags.KindTime:
+		return v + ".IsZero()"
+	case tags.KindInterface:
+		return v + " == nil"
+	}
+	return "false"
+}
+
+// encodeField generates the code setting av to the value v of the
+// field.
+func (g *generator) encodeField(f *field) {
+	if f.ttl {
+		g.p("if !v.IsZero() {")
+		defer g.p("}")
+	}
+	switch {
+	case f.custom && f.fmt == "{}":
+		g.encodeCustom(f)
+	case f.attrType == "S" && f.fmt == "{}" && kindOf(f.typ) == tags.KindString:
+		g.p("s := string(v)")
+		g.p("av = &dynamodb.AttributeValue{S: &s}")
+	case f.attrType == "S":
+		g.p("var b strings.Builder")
+		for i, p := range f.tmpl.Parts {
+			if !p.Placeholder {
+				g.p("b.WriteString(%q)", p.Literal)
+				continue
+			}
+			s := g.v("s")
+			g.p("var %s string", s)
+			g.formatPlaceholder(f, f.refs[i], s)
+			var next string
+			if i+1 < len(f.tmpl.Parts) {
+				next = f.tmpl.Parts[i+1].Literal
+			}
+			if p.Width > 0 || next != "" {
+				g.p("%s = %s.FmtValue(%s, %d, %t, %q)", s, g.use(dynagoPath), s, p.Width, p.Zero, next)
+			}
+			g.p("b.WriteString(%s)", s)
+		}
+		g.p("s := b.String()")
+		g.p("av = &dynamodb.AttributeValue{S: &s}")
+	case tags.IsSetType(f.attrType):
+		el := f.typ.Underlying().(*types.Slice).Elem()
+		g.p("set := &dynamodb.AttributeValue{}")
+		g.p("seen := make(map[string]bool)")
+		g.p("for _, el := range v {")
+		switch f.attrType {
+		case "SS":
+			g.p("s := string(el)")
+			g.p("if !seen[s] {")
+			g.p("set.SS = append(set.SS, &s)")
+			g.p("}")
+			g.p("seen[s] = true")
+		case "NS":
+			g.p("s := %s", g.formatNumber("el", el))
+			g.p("if !seen[s] {")
+			g.p("set.NS = append(set.NS, &s)")
+			g.p("}")
+			g.p("seen[s] = true")
+		case "BS":
+			g.p("if !seen[string(el)] {")
+			g.p("set.BS = append(set.BS, []byte(el))")
+			g.p("}")
+			g.p("seen[string(el)] = true")
+		}
+		g.p("}")
+		g.p("if len(set.%s) > 0 {", f.attrType)
+		g.p("av = set")
+		g.p("}")
+	default:
+		g.encodeSimple("av", "v", f.typ, f.layout)
+	}
+}
+
+// encodeCustom generates the code marshalling v with its
+// AttributeMarshaler or encoding.TextMarshaler implementation.
+func (g *generator) encodeCustom(f *field) {
+	has := func(m string) bool { return hasPtrMethod(f.typ, m) }
+	if hasMethod(f.typ, "MarshalAttribute") || hasMethod(f.typ, "MarshalText") {
+		has = func(m string) bool { return hasMethod(f.typ, m) }
+	}
+	if has("MarshalAttribute") {
+		g.p("var err error")
+		g.p("av, err = v.MarshalAttribute()")
+		g.p("if err != nil {")
+		g.p(`return nil, %s.Errorf("dynago: attribute %s: MarshalAttribute: %%w", err)`, g.use("fmt"), f.attrName)
+		g.p("}")
+		return
+	}
+	g.p("b, err := v.MarshalText()")
+	g.p("if err != nil {")
+	g.p(`return nil, %s.Errorf("dynago: attribute %s: MarshalText: %%w", err)`, g.use("fmt"), f.attrName)
+	g.p("}")
+	g.p("s := string(b)")
+	g.p("av = &dynamodb.AttributeValue{S: &s}")
+}
+
+// formatPlaceholder generates the code setting s to the formatted value
+// of the field pf of a placeholder of the template of f.
+func (g *generator) formatPlaceholder(f *field, pf *field, s string) {
+	closing := g.guard(pf, false)
+	val := "x." + pf.selector()
+	if pf.ptr {
+		g.p("if %s != nil {", val)
+		val = "*" + val
+		closing++
+	}
+	g.p("{")
+	g.p("pv := %s", val)
+	ty, _ := deref(pf.raw())
+	switch k := kindOf(ty); {
+	case k != tags.KindTime && (hasMethod(ty, "MarshalText") || hasPtrMethod(ty, "MarshalText")):
+		g.p("b, err := pv.MarshalText()")
+		g.p("if err != nil {")
+		g.p(`return nil, %s.Errorf("dynago: attribute %s: MarshalText: %%w", err)`, g.use("fmt"), f.attrName)
+		g.p("}")
+		g.p("%s = string(b)", s)
+	case k == tags.KindString:
+		g.p("%s = string(pv)", s)
+	case k == tags.KindBool:
+		g.p("%s = %s.FormatBool(bool(pv))", s, g.use("strconv"))
+	case k == tags.KindTime:
+		g.p("%s = %s.FormatTime(pv, %q)", s, g.use(dynagoPath), pf.layout)
+	default:
+		g.p("%s = %s", s, g.formatNumber("pv", ty))
+	}
+	g.p("}")
+	g.close(closing)
+}
+
+// formatNumber returns the expression formatting the number v of the
+// type t.
+func (g *generator) formatNumber(v string, t types.Type) string {
+	strconv := g.use("strconv")
+	switch kindOf(t) {
+	case tags.KindInt:
+		return fmt.Sprintf("%s.FormatInt(int64(%s), 10)", strconv, v)
+	case tags.KindUint:
+		return fmt.Sprintf("%s.FormatUint(uint64(%s), 10)", strconv, v)
+	}
+	return fmt.Sprintf("%s.FormatFloat(float64(%s), 'f', -1, %d)", strconv, v, bits(t))
+}
+
+// encodeSimple generates the code setting av to the value v of the
+// type t, marshalled by its kind.
+func (g *generator) encodeSimple(av string, v string, t types.Type, layout string) {
+	switch kindOf(t) {
+	case tags.KindString:
+		g.p("{")
+		g.p("s := string(%s)", v)
+		g.p("%s = &dynamodb.AttributeValue{S: &s}", av)
+		g.p("}")
+	case tags.KindInt, tags.KindUint, tags.KindFloat:
+		g.p("{")
+		g.p("s := %s", g.formatNumber(v, t))
+		g.p("%s = &dynamodb.AttributeValue{N: &s}", av)
+		g.p("}")
+	case tags.KindBool:
+		g.p("{")
+		g.p("b := bool(%s)", v)
+		g.p("%s = &dynamodb.AttributeValue{BOOL: &b}", av)
+		g.p("}")
+	case tags.KindTime:
+		g.p("{")
+		g.p("s := %s.FormatTime(%s, %q)", g.use(dynagoPath), v, layout)
+		if tags.IsUnixLayout(layout) {
+			g.p("%s = &dynamodb.AttributeValue{N: &s}", av)
+		} else {
+			g.p("%s = &dynamodb.AttributeValue{S: &s}", av)
+		}
+		g.p("}")
+	case tags.KindBytes:
+		g.p("%s = &dynamodb.AttributeValue{B: []byte(%s)}", av, v)
+	case tags.KindSlice:
+		el := g.v("el")
+		elAv := g.v("av")
+		g.p("{")
+		g.p("l := &dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{}}")
+		g.p("for _, %s := range %s {", el, v)
+		g.p("var %s *dynamodb.AttributeValue", elAv)
+		g.encodeSimple(elAv, el, t.Underlying().(*types.Slice).Elem(), layout)
+		g.p("l.L = append(l.L, %s)", elAv)
+		g.p("}")
+		g.p("%s = l", av)
+		g.p("}")
+	}
+}
+
+// decodeField generates the code unmarshalling av into the field, whose
+// dereferenced value is target.
+func (g *generator) decodeField(f *field, target string) {
+	switch {
+	case f.custom && f.fmt == "{}":
+		if hasPtrMethod(f.typ, "UnmarshalAttribute") {
+			g.p("if err := %s.UnmarshalAttribute(av); err != nil {", target)
+			g.p(`return %s.Errorf("UnmarshalAttribute: %%w", err)`, g.use("fmt"))
+			g.p("}")
+			return
+		}
+		g.p("if av.S != nil {")
+		g.p("if err := %s.UnmarshalText([]byte(*av.S)); err != nil {", target)
+		g.p(`return %s.Errorf("UnmarshalText: %%w", err)`, g.use("fmt"))
+		g.p("}")
+		g.p("}")
+	case f.attrType == "S" && f.fmt == "{}" && kindOf(f.typ) == tags.KindString:
+		g.p("if av.S != nil {")
+		g.p("%s = %s(*av.S)", target, g.typ(f.typ))
+		g.p("}")
+	case f.attrType == "S":
+		g.p("if av.S != nil {")
+		var lits []string
+		var placeholders []string
+		for _, p := range f.tmpl.Parts {
+			lits = append(lits, strconv.Quote(p.Literal))
+			placeholders = append(placeholders, strconv.FormatBool(p.Placeholder))
+		}
+		g.p("if values, ok := %s.ScanFmt(*av.S, []string{%s}, []bool{%s}); ok {", g.use(dynagoPath), strings.Join(lits, ", "), strings.Join(placeholders, ", "))
+		for i, p := range f.tmpl.Parts {
+			if p.Placeholder {
+				g.parsePlaceholder(p, f.refs[i], fmt.Sprintf("values[%d]", i))
+			}
+		}
+		g.p("}")
+		if f.fmt == "{}" {
+			g.p("} else if av.N != nil {")
+			g.errNumber(target, f.layout)
+		}
+		g.p("}")
+	case tags.IsSetType(f.attrType):
+		g.p("var els []*dynamodb.AttributeValue")
+		g.p("for _, s := range av.SS {")
+		g.p("els = append(els, &dynamodb.AttributeValue{S: s})")
+		g.p("}")
+		g.p("for _, n := range av.NS {")
+		g.p("els = append(els, &dynamodb.AttributeValue{N: n})")
+		g.p("}")
+		g.p("for _, b := range av.BS {")
+		g.p("els = append(els, &dynamodb.AttributeValue{B: b})")
+		g.p("}")
+		g.p("if len(els) == 0 && av.L != nil {")
+		g.p("els = av.L")
+		g.p("}")
+		g.decodeList(target, "els", f.typ, f.layout)
+	default:
+		g.decodeSimple(target, "av", f.typ, f.layout)
+	}
+}
+
+// parsePlaceholder generates the code parsing the string str into the
+// field pf of the placeholder p.
+func (g *generator) parsePlaceholder(p *tags.Part, pf *field, str string) {
+	g.p("{")
+	g.p("str := %s", str)
+	if p.Width > 0 && !p.Zero {
+		g.p(`str = %s.TrimLeft(str, " ")`, g.use("strings"))
+	}
+	g.guard(pf, true)
+	target := "x." + pf.selector()
+	if pf.ptr {
+		g.p("if %s == nil {", target)
+		g.p("%s = new(%s)", target, g.typ(pf.typ))
+		g.p("}")
+		target = "(*" + target + ")"
+	}
+	fmtPkg := g.use("fmt")
+	fail := func(call string) {
+		g.p("if err != nil {")
+		g.p(`return %s.Errorf("parse: field %s: %s%%s", err)`, fmtPkg, pf.name, call)
+		g.p("}")
+	}
+	ty := pf.typ
+	switch k := kindOf(ty); {
+	case k != tags.KindTime && hasPtrMethod(ty, "UnmarshalText"):
+		g.p("err := %s.UnmarshalText([]byte(str))", target)
+		fail("UnmarshalText: ")
+	case k == tags.KindString:
+		g.p("%s = %s(str)", target, g.typ(ty))
+	case k == tags.KindBool:
+		g.p("b, err := %s.ParseBool(str)", g.use("strconv"))
+		fail("")
+		g.p("%s = %s(b)", target, g.typ(ty))
+	case k == tags.KindInt:
+		g.p("n, err := %s.ParseInt(str, 10, %d)", g.use("strconv"), bits(ty))
+		fail("")
+		g.p("%s = %s(n)", target, g.typ(ty))
+	case k == tags.KindUint:
+		g.p("n, err := %s.ParseUint(str, 10, %d)", g.use("strconv"), bits(ty))
+		fail("")
+		g.p("%s = %s(n)", target, g.typ(ty))
+	case k == tags.KindFloat:
+		g.p("n, err := %s.ParseFloat(str, %d)", g.use("strconv"), bits(ty))
+		fail("")
+		g.p("%s = %s(n)", target, g.typ(ty))
+	case k == tags.KindTime:
+		g.p("t, err := %s.ParseTime(str, %q)", g.use(dynagoPath), pf.layout)
+		fail("parseTime: ")
+		g.p("%s = t", target)
+	}
+	g.p("}")
+}
+
+// decodeSimple generates the code unmarshalling the attribute value av
+// into target of the type t by its kind.
+func (g *generator) decodeSimple(target string, av string, t types.Type, layout string) {
+	switch kindOf(t) {
+	case tags.KindString:
+		g.p("if %s.S != nil {", av)
+		g.p("%s = %s(*%s.S)", target, g.typ(t), av)
+		g.p("}")
+	case tags.KindInt:
+		g.p("if %s.N != nil {", av)
+		g.p("n, err := %s.ParseInt(*%s.N, 10, %d)", g.use("strconv"), av, bits(t))
+		g.p("if err != nil {")
+		g.p("return err")
+		g.p("}")
+		g.p("%s = %s(n)", target, g.typ(t))
+		g.p("}")
+	case tags.KindUint:
+		g.p("if %s.N != nil {", av)
+		g.p("n, err := %s.ParseUint(*%s.N, 10, %d)", g.use("strconv"), av, bits(t))
+		g.p("if err != nil {")
+		g.p("return err")
+		g.p("}")
+		g.p("%s = %s(n)", target, g.typ(t))
+		g.p("}")
+	case tags.KindFloat:
+		g.p("if %s.N != nil {", av)
+		g.p("n, err := %s.ParseFloat(*%s.N, %d)", g.use("strconv"), av, bits(t))
+		g.p("if err != nil {")
+		g.p("return err")
+		g.p("}")
+		g.p("%s = %s(n)", target, g.typ(t))
+		g.p("}")
+	case tags.KindBool:
+		g.p("if %s.BOOL != nil {", av)
+		g.p("%s = %s(*%s.BOOL)", target, g.typ(t), av)
+		g.p("}")
+	case tags.KindTime:
+		g.p("if %s.N != nil {", av)
+		if tags.IsUnixLayout(layout) {
+			g.p("t, err := %s.ParseTime(*%s.N, %q)", g.use(dynagoPath), av, layout)
+			g.p("if err != nil {")
+			g.p("return err")
+			g.p("}")
+			g.p("%s = t", target)
+		} else {
+			g.errNumber(target, layout)
+		}
+		g.p("} else if %s.S != nil {", av)
+		g.p("t, err := %s.ParseTime(*%s.S, %q)", g.use(dynagoPath), av, layout)
+		g.p("if err != nil {")
+		g.p("return err")
+		g.p("}")
+		g.p("%s = t", target)
+		g.p("}")
+	case tags.KindBytes:
+		g.p("if %s.B != nil {", av)
+		g.p("%s = %s(%s.B)", target, g.typ(t), av)
+		g.p("}")
+	case tags.KindSlice:
+		g.decodeList(target, av+".L", t, layout)
+	}
+}
+
+// errNumber generates the code returning the error of unmarshalling a
+// Number into target, which is stored as a String with the layout.
+func (g *generator) errNumber(target string, layout string) {
+	g.p(`return %s.Errorf("dynago: type mismatch: can not unmarshal N into %%T with layout %%q", %s, %q)`, g.use("fmt"), target, layout)
+}
+
+// decodeList generates the code unmarshalling the attribute values els
+// into target, a slice of the type t.
+func (g *generator) decodeList(target string, els string, t types.Type, layout string) {
+	sl := g.v("sl")
+	el := g.v("el")
+	i := g.v("i")
+	g.p("%s := make(%s, len(%s))", sl, g.typ(t), els)
+	g.p("for %s, %s := range %s {", i, el, els)
+	g.p("if %s == nil || (%s.NULL != nil && *%s.NULL) {", el, el, el)
+	g.p("continue")
+	g.p("}")
+	g.decodeSimple(sl+"["+i+"]", el, t.Underlying().(*types.Slice).Elem(), layout)
+	g.p("}")
+	g.p("%s = %s", target, sl)
+}
+
+// helpers generates the functions used by the generated methods.
+func (g *generator) helpers() {
+	g.p("")
+	g.p("// dynagoConfig is the configuration the methods were generated for.")
+	g.p("var dynagoConfig = %s.GeneratedConfig{", g.use(dynagoPath))
+	g.p(`AttrTagName: "attr",`)
+	g.p(`FmtTagName: "fmt",`)
+	g.p(`TypeTagName: "type",`)
+	g.p(`LayoutTagName: "layout",`)
+	g.p(`AttrsToCopyTagName: "copy",`)
+	g.p(`VersionTagName: "version",`)
+	g.p(`AutoTagName: "auto",`)
+	g.p(`TTLTagName: "ttl",`)
+	g.p("DefaultOmitEmpty: %t,", g.omitEmpty)
+	g.p("NullPointers: %t,", g.null)
+	g.p("}")
+	g.buf.WriteString(helpers)
+}
+
+// config generates the DynagoConfig method of the struct, with which
+// dynago checks that the methods match the configuration of a client.
+func (g *generator) config(name string) {
+	g.p("")
+	g.p("// DynagoConfig returns the configuration the methods of the %s were", name)
+	g.p("// generated for.")
+	g.p("func (*%s) DynagoConfig() %s.GeneratedConfig {", name, g.use(dynagoPath))
+	g.p("c := dynagoConfig")
+	g.p("c.Type = %s.TypeOf((*%s)(nil)).Elem()", g.use("reflect"), name)
+	g.p("return c")
+	g.p("}")
+}
+
+const helpers = `
+func dynagoBool(b bool) *bool {
+	return &b
+}
+`
@@ -0,0 +1,72 @@
+// Command dynago-gen generates MarshalDynago and UnmarshalDynago
+// methods for the structs of a package that implement dynago.Keyer.
+// dynago.Marshal and dynago.Unmarshal use them instead of reflection.
+//
+// It is meant to be run with go generate from the package directory:
+//
+//	//go:generate go run github.com/twharmon/dynago/cmd/dynago-gen
+//
+// The attr, fmt, type, layout, copy, version, auto and ttl tags are
+// read with their default names. The generated methods produce the
+// same items as the reflective path of a client whose Config has the
+// default tag names, and the same DefaultOmitEmpty and NullPointers as
+// the -omitempty and -nullpointers flags. A DynagoConfig method
+// returning this configuration is generated too, and clients
+// configured differently, such as with any tag name that is not the
+// default, use reflection instead. Structs with fields the generator does not
+// support, such as nested structs, maps or interfaces, are skipped
+// with a warning and keep using reflection.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const defaultOutput = "dynago_gen.go"
+
+var (
+	typeNames = flag.String("type", "", "comma separated list of struct names; defaults to all structs implementing dynago.Keyer")
+	output    = flag.String("output", "", "output file name; defaults to "+defaultOutput+" in the package directory")
+	omitEmpty = flag.Bool("omitempty", false, "generate code for a client with Config.DefaultOmitEmpty set")
+	nullPtrs  = flag.Bool("nullpointers", false, "generate code for a client with Config.NullPointers set")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: dynago-gen [flags] [directory]\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("dynago-gen: ")
+	flag.Usage = usage
+	flag.Parse()
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+	out := *output
+	if out == "" {
+		out = filepath.Join(dir, defaultOutput)
+	}
+	g := generator{
+		omitEmpty: *omitEmpty,
+		null:      *nullPtrs,
+		warn:      log.Printf,
+	}
+	if *typeNames != "" {
+		g.types = strings.Split(*typeNames, ",")
+	}
+	src, err := g.generate(dir, out)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateUpToDate(t *testing.T) {
+	dir := filepath.Join("..", "..", "internal", "gentest")
+	out := filepath.Join(dir, defaultOutput)
+	var warnings []string
+	g := generator{warn: func(format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}}
+	got, err := g.generate(dir, out)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	want, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	if !bytes.Equal(want, got) {
+		t.Fatalf("%s is out of date, run go generate", out)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "skipping Meta") {
+		t.Fatalf("unexpected warnings %q", warnings)
+	}
+}
+
+func TestGenerateTypeErr(t *testing.T) {
+	for _, tc := range []struct {
+		src string
+		ok  bool
+	}{
+		{"package p\n\ntype Item struct{ ID string }\n\nvar _ = (*Item).MarshalDynago\n", true},
+		{"package p\n\ntype Item struct{ ID Missing }\n", false},
+	} {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "p.go"), []byte(tc.src), 0o644); err != nil {
+			t.Fatalf("unexpected err: %s", err)
+		}
+		g := generator{types: []string{"Item"}, warn: t.Logf}
+		_, err := g.generate(dir, filepath.Join(dir, defaultOutput))
+		if tc.ok && err != nil {
+			t.Fatalf("unexpected err: %s", err)
+		}
+		if !tc.ok && (err == nil || !strings.Contains(err.Error(), "Missing")) {
+			t.Fatalf("expected err about Missing, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package dynago
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/twharmon/dynago/internal/tags"
+)
+
+// codec is the compiled plan to marshal and unmarshal values of a
+// struct type. It is built once per type, after which marshalling
+// only walks the precomputed fields.
+type codec struct {
+	fields  []*field
+	attrs   []*field
+	byAttr  map[string]*field
+	version *field
+	ttl     *field
+	auto    []*field
+}
+
+// encoder marshals fv, the dereferenced value of a field of the struct
+// value v.
+type encoder func(v reflect.Value, fv reflect.Value) (*dynamodb.AttributeValue, error)
+
+// decoder unmarshals av into fv, the dereferenced value of a field of
+// the struct value v.
+type decoder func(v reflect.Value, fv reflect.Value, av *dynamodb.AttributeValue) error
+
+// codec returns the codec of the struct type ty, compiling it the
+// first time the type is seen.
+func (d *Dynago) codec(ty reflect.Type) (*codec, error) {
+	if c, ok := d.codecs.Load(ty); ok {
+		return c.(*codec), nil
+	}
+	c, err := d.compile(ty)
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := d.codecs.LoadOrStore(ty, c)
+	return actual.(*codec), nil
+}
+
+// compile builds the codec of the struct type ty.
+func (d *Dynago) compile(ty reflect.Type) (*codec, error) {
+	fields, err := d.fields(ty)
+	if err != nil {
+		return nil, fmt.Errorf("d.fields: %w", err)
+	}
+	c := codec{fields: fields, byAttr: make(map[string]*field)}
+	for _, f := range fields {
+		if f.attrName == "-" {
+			continue
+		}
+		f.encode = f.encoder()
+		f.decode = f.decoder()
+		c.attrs = append(c.attrs, f)
+		c.byAttr[f.attrName] = f
+		if f.version && c.version == nil {
+			c.version = f
+		}
+		if f.ttl && c.ttl == nil {
+			c.ttl = f
+		}
+		if f.auto != "" {
+			c.auto = append(c.auto, f)
+		}
+	}
+	// Attribute names take precedence over copies.
+	for _, f := range c.attrs {
+		for _, cp := range f.attrsToCopy {
+			if _, ok := c.byAttr[cp]; !ok {
+				c.byAttr[cp] = f
+			}
+		}
+	}
+	return &c, nil
+}
+
+// marshal converts the struct value v into a DynamoDB item.
+func (c *codec) marshal(v reflect.Value) (map[string]*dynamodb.AttributeValue, error) {
+	m := make(map[string]*dynamodb.AttributeValue, len(c.attrs))
+	for _, f := range c.attrs {
+		omit, attrVal := f.omit(v)
+		if omit {
+			continue
+		}
+		if attrVal == nil {
+			var err error
+			attrVal, err = f.attrVal(v)
+			if err != nil {
+				return nil, fmt.Errorf("f.attrVal: %w", err)
+			}
+		}
+		if attrVal == nil {
+			continue
+		}
+		m[f.attrName] = attrVal
+		for _, cp := range f.attrsToCopy {
+			m[cp] = attrVal
+		}
+	}
+	return m, nil
+}
+
+// unmarshal converts the DynamoDB item into the struct value v.
+func (c *codec) unmarshal(item map[string]*dynamodb.AttributeValue, v reflect.Value) error {
+	for _, f := range c.attrs {
+		if err := f.unmarshal(item, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encoder returns the encoder of the field, chosen from its type and
+// tags.
+func (f *field) encoder() encoder {
+	d := f.client
+	switch {
+	case f.custom && f.fmt == "{}":
+		return func(_ reflect.Value, fv reflect.Value) (*dynamodb.AttributeValue, error) {
+			return d.simpleMarshal(fv, f.layout)
+		}
+	case f.attrType == "S" && f.fmt == "{}" && f.typ.Kind() == reflect.String:
+		return func(_ reflect.Value, fv reflect.Value) (*dynamodb.AttributeValue, error) {
+			s := fv.String()
+			return &dynamodb.AttributeValue{S: &s}, nil
+		}
+	case f.attrType == "S":
+		return func(v reflect.Value, _ reflect.Value) (*dynamodb.AttributeValue, error) {
+			s, err := f.format(v)
+			if err != nil {
+				return nil, err
+			}
+			return &dynamodb.AttributeValue{S: s}, nil
+		}
+	case tags.IsSetType(f.attrType):
+		return func(_ reflect.Value, fv reflect.Value) (*dynamodb.AttributeValue, error) {
+			return d.marshalSet(fv, f.attrType, f.layout)
+		}
+	}
+	switch f.typ.Kind() {
+	case reflect.String:
+		return func(_ reflect.Value, fv reflect.Value) (*dynamodb.AttributeValue, error) {
+			s := fv.String()
+			return &dynamodb.AttributeValue{S: &s}, nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(_ reflect.Value, fv reflect.Value) (*dynamodb.AttributeValue, error) {
+			s := strconv.FormatInt(fv.Int(), 10)
+			return &dynamodb.AttributeValue{N: &s}, nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return func(_ reflect.Value, fv reflect.Value) (*dynamodb.AttributeValue, error) {
+			s := strconv.FormatUint(fv.Uint(), 10)
+			return &dynamodb.AttributeValue{N: &s}, nil
+		}
+	case reflect.Float32, reflect.Float64:
+		bits := f.typ.Bits()
+		return func(_ reflect.Value, fv reflect.Value) (*dynamodb.AttributeValue, error) {
+			s := strconv.FormatFloat(fv.Float(), 'f', -1, bits)
+			return &dynamodb.AttributeValue{N: &s}, nil
+		}
+	case reflect.Bool:
+		return func(_ reflect.Value, fv reflect.Value) (*dynamodb.AttributeValue, error) {
+			b := fv.Bool()
+			return &dynamodb.AttributeValue{BOOL: &b}, nil
+		}
+	}
+	return func(_ reflect.Value, fv reflect.Value) (*dynamodb.AttributeValue, error) {
+		return d.simpleMarshal(fv, f.layout)
+	}
+}
+
+// decoder returns the decoder of the field, chosen from its type and
+// tags.
+func (f *field) decoder() decoder {
+	d := f.client
+	switch {
+	case f.custom && f.fmt == "{}":
+		return func(_ reflect.Value, fv reflect.Value, av *dynamodb.AttributeValue) error {
+			return d.simpleUnmarshal(fv, av, f.layout)
+		}
+	case f.attrType == "S" && f.fmt == "{}" && f.typ.Kind() == reflect.String:
+		return func(_ reflect.Value, fv reflect.Value, av *dynamodb.AttributeValue) error {
+			if av.S != nil {
+				fv.SetString(*av.S)
+			}
+			return nil
+		}
+	case f.attrType == "S":
+		return func(v reflect.Value, fv reflect.Value, av *dynamodb.AttributeValue) error {
+			switch {
+			case av.S != nil:
+				if err := f.parse(*av.S, v); err != nil {
+					return fmt.Errorf("parse: %s", err)
+				}
+			case av.N != nil && f.fmt == "{}":
+				return errNumber(fv.Type(), f.layout)
+			}
+			return nil
+		}
+	case tags.IsSetType(f.attrType):
+		return func(_ reflect.Value, fv reflect.Value, av *dynamodb.AttributeValue) error {
+			return d.unmarshalSet(fv, av, f.layout)
+		}
+	}
+	return func(_ reflect.Value, fv reflect.Value, av *dynamodb.AttributeValue) error {
+		return d.simpleUnmarshal(fv, av, f.layout)
+	}
+}
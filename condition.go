@@ -0,0 +1,262 @@
+package dynago
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Operand is an attribute, or the size of an attribute, used to build
+// a Condition.
+type Operand struct {
+	path string
+	size bool
+}
+
+// Condition represents a ConditionExpression or FilterExpression.
+// Values compared to a top level attribute are marshalled as the
+// attribute with the same name of the item's struct, so its fmt tag is
+// applied to them. Comparing to an Operand compares to the value of
+// that attribute instead.
+type Condition struct {
+	op      string
+	operand Operand
+	values  []interface{}
+	conds   []*Condition
+}
+
+// Attr returns an Operand for the attribute at the given document
+// path, such as "Name", "Address.City" or "Tags[0]".
+func Attr(path string) Operand {
+	return Operand{path: path}
+}
+
+// Size returns an Operand for the size of the attribute.
+func (o Operand) Size() Operand {
+	return Operand{path: o.path, size: true}
+}
+
+func (o Operand) compare(op string, values ...interface{}) *Condition {
+	return &Condition{op: op, operand: o, values: values}
+}
+
+// Equal returns a condition that the operand is equal to v.
+func (o Operand) Equal(v interface{}) *Condition {
+	return o.compare("=", v)
+}
+
+// NotEqual returns a condition that the operand is not equal to v.
+func (o Operand) NotEqual(v interface{}) *Condition {
+	return o.compare("<>", v)
+}
+
+// LessThan returns a condition that the operand is less than v.
+func (o Operand) LessThan(v interface{}) *Condition {
+	return o.compare("<", v)
+}
+
+// LessThanEqual returns a condition that the operand is less than or
+// equal to v.
+func (o Operand) LessThanEqual(v interface{}) *Condition {
+	return o.compare("<=", v)
+}
+
+// GreaterThan returns a condition that the operand is greater than v.
+func (o Operand) GreaterThan(v interface{}) *Condition {
+	return o.compare(">", v)
+}
+
+// GreaterThanEqual returns a condition that the operand is greater
+// than or equal to v.
+func (o Operand) GreaterThanEqual(v interface{}) *Condition {
+	return o.compare(">=", v)
+}
+
+// Between returns a condition that the operand is greater than or
+// equal to lower and less than or equal to upper.
+func (o Operand) Between(lower interface{}, upper interface{}) *Condition {
+	return o.compare("BETWEEN", lower, upper)
+}
+
+// In returns a condition that the operand is equal to any of the
+// values. Building it fails if there are no values.
+func (o Operand) In(values ...interface{}) *Condition {
+	return o.compare("IN", values...)
+}
+
+// Exists returns a condition that the attribute exists.
+func (o Operand) Exists() *Condition {
+	return o.compare("attribute_exists")
+}
+
+// NotExists returns a condition that the attribute does not exist.
+func (o Operand) NotExists() *Condition {
+	return o.compare("attribute_not_exists")
+}
+
+// Type returns a condition that the attribute is of the given
+// DynamoDB type, such as "S", "N" or "L".
+func (o Operand) Type(t string) *Condition {
+	return o.compare("attribute_type", t)
+}
+
+// BeginsWith returns a condition that the attribute begins with
+// prefix. The fmt template of the attribute is cut after the
+// placeholder prefix is substituted in.
+func (o Operand) BeginsWith(prefix interface{}) *Condition {
+	return o.compare("begins_with", prefix)
+}
+
+// Contains returns a condition that the attribute is a string
+// containing the substring v, or a set or list containing the
+// element v.
+func (o Operand) Contains(v interface{}) *Condition {
+	return o.compare("contains", v)
+}
+
+// And returns a condition that all of the given conditions are true.
+// Nil conditions are ignored, and nil is returned if there are none
+// left, so conditions can be built up dynamically.
+func And(conds ...*Condition) *Condition {
+	return join("AND", conds)
+}
+
+// Or returns a condition that any of the given conditions is true.
+// Nil conditions are ignored, and nil is returned if there are none
+// left.
+func Or(conds ...*Condition) *Condition {
+	return join("OR", conds)
+}
+
+// Not returns a condition that the given condition is false.
+func Not(c *Condition) *Condition {
+	if c == nil {
+		return nil
+	}
+	return &Condition{op: "NOT", conds: []*Condition{c}}
+}
+
+// And returns a condition that c and all of the others are true.
+func (c *Condition) And(others ...*Condition) *Condition {
+	return And(append([]*Condition{c}, others...)...)
+}
+
+// Or returns a condition that c or any of the others is true.
+func (c *Condition) Or(others ...*Condition) *Condition {
+	return Or(append([]*Condition{c}, others...)...)
+}
+
+// Not returns a condition that c is false.
+func (c *Condition) Not() *Condition {
+	return Not(c)
+}
+
+func join(op string, conds []*Condition) *Condition {
+	var flat []*Condition
+	for _, c := range conds {
+		switch {
+		case c == nil:
+		case c.op == op:
+			flat = append(flat, c.conds...)
+		default:
+			flat = append(flat, c)
+		}
+	}
+	switch len(flat) {
+	case 0:
+		return nil
+	case 1:
+		return flat[0]
+	}
+	return &Condition{op: op, conds: flat}
+}
+
+func (c *Condition) build(e *expression) (string, error) {
+	switch c.op {
+	case "AND", "OR":
+		parts := make([]string, len(c.conds))
+		for i, cond := range c.conds {
+			s, err := cond.build(e)
+			if err != nil {
+				return "", err
+			}
+			if cond.op == "AND" || cond.op == "OR" {
+				s = "(" + s + ")"
+			}
+			parts[i] = s
+		}
+		return strings.Join(parts, " "+c.op+" "), nil
+	case "NOT":
+		s, err := c.conds[0].build(e)
+		if err != nil {
+			return "", err
+		}
+		return "NOT (" + s + ")", nil
+	}
+	left, err := e.path(c.operand.path)
+	if err != nil {
+		return "", err
+	}
+	if c.operand.size {
+		left = "size(" + left + ")"
+	}
+	vals := make([]string, len(c.values))
+	for i, v := range c.values {
+		s, err := c.value(e, v)
+		if err != nil {
+			return "", fmt.Errorf("attribute %s: %w", c.operand.path, err)
+		}
+		vals[i] = s
+	}
+	switch c.op {
+	case "BETWEEN":
+		return fmt.Sprintf("%s BETWEEN %s AND %s", left, vals[0], vals[1]), nil
+	case "IN":
+		if len(vals) == 0 {
+			return "", fmt.Errorf("dynago: attribute %s: IN must have at least one value", c.operand.path)
+		}
+		return fmt.Sprintf("%s IN (%s)", left, strings.Join(vals, ", ")), nil
+	case "attribute_exists", "attribute_not_exists", "attribute_type", "begins_with", "contains":
+		return fmt.Sprintf("%s(%s)", c.op, strings.Join(append([]string{left}, vals...), ", ")), nil
+	}
+	return fmt.Sprintf("%s %s %s", left, c.op, vals[0]), nil
+}
+
+// value returns the placeholder of a value of the condition.
+func (c *Condition) value(e *expression, v interface{}) (string, error) {
+	if o, ok := v.(Operand); ok {
+		path, err := e.path(o.path)
+		if err != nil || !o.size {
+			return path, err
+		}
+		return "size(" + path + ")", nil
+	}
+	// The fmt tag applies to values compared to top level attributes
+	// only. Sizes, types and elements of lists and sets are
+	// marshalled as is.
+	attrName, err := e.dynago.attrPath(e.ty, c.operand.path)
+	if err != nil {
+		return "", err
+	}
+	if c.operand.size || c.op == "attribute_type" || c.op == "contains" || strings.ContainsAny(attrName, ".[") {
+		attrName = ""
+	}
+	return e.value(attrName, v, c.op == "begins_with")
+}
+
+// condition builds the condition into an expression, adding its
+// placeholders to names and values. The expression is combined with
+// AND with exp, the expression of an earlier condition, if any. Exp is
+// returned if c is nil.
+func (d *Dynago) condition(exp *string, c *Condition, prefix string, ty reflect.Type, names *map[string]*string, values *map[string]*dynamodb.AttributeValue) (*string, error) {
+	if c == nil {
+		return exp, nil
+	}
+	cond, err := c.build(d.expression(prefix, ty, names, values))
+	if err != nil {
+		return nil, err
+	}
+	return andCondition(exp, cond), nil
+}
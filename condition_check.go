@@ -42,6 +42,20 @@ func (q *ConditionCheck) ConditionExpression(exp string) *ConditionCheck {
 	return q
 }
 
+// Condition sets the ConditionExpression from the given condition.
+// Placeholders for attribute names and values are generated and
+// added to ExpressionAttributeNames and ExpressionAttributeValues.
+// Conditions of repeated calls are combined with AND.
+func (q *ConditionCheck) Condition(c *Condition) *ConditionCheck {
+	exp, err := q.dynago.condition(q.check.ConditionExpression, c, "c", itemType(q.item), &q.check.ExpressionAttributeNames, &q.check.ExpressionAttributeValues)
+	if err != nil {
+		q.err = fmt.Errorf("dynago: ConditionCheck.Condition: %w", err)
+		return q
+	}
+	q.check.ConditionExpression = exp
+	return q
+}
+
 // ExpressionAttributeValue sets an ExpressionAttributeValue.
 func (q *ConditionCheck) ExpressionAttributeValue(key string, val interface{}, layout ...string) *ConditionCheck {
 	if q.check.ExpressionAttributeValues == nil {
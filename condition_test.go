@@ -0,0 +1,261 @@
+package dynago_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/twharmon/dynago"
+)
+
+func TestConditionPutItem(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+	}
+	p := Person{Name: "foo"}
+	ddb.MockPut(&dynamodb.PutItemInput{
+		TableName: aws.String("foo"),
+		Item: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Person#foo")},
+		},
+		ConditionExpression: aws.String("attribute_not_exists(#c0)"),
+		ExpressionAttributeNames: map[string]*string{
+			"#c0": aws.String("PK"),
+		},
+	})
+	if err := client.PutItem(&p).Condition(dynago.Attr("PK").NotExists()).Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	ddb.done()
+}
+
+func TestConditionPutItemTwice(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64
+	}
+	p := Person{Name: "foo"}
+	ddb.MockPut(&dynamodb.PutItemInput{
+		TableName: aws.String("foo"),
+		Item: map[string]*dynamodb.AttributeValue{
+			"PK":  {S: aws.String("Person#foo")},
+			"Age": {N: aws.String("0")},
+		},
+		ConditionExpression: aws.String("(#c0 < :c0) AND (attribute_not_exists(#c1))"),
+		ExpressionAttributeNames: map[string]*string{
+			"#c0": aws.String("Age"),
+			"#c1": aws.String("PK"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":c0": {N: aws.String("30")},
+		},
+	})
+	if err := client.PutItem(&p).
+		Condition(dynago.Attr("Age").LessThan(30)).
+		Condition(dynago.Attr("PK").NotExists()).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	ddb.done()
+}
+
+func TestConditionScanFilterTwice(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Person struct {
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64
+	}
+	ddb.MockScan(&dynamodb.ScanInput{
+		TableName:        aws.String("foo"),
+		ConsistentRead:   aws.Bool(false),
+		FilterExpression: aws.String("(#f0 >= :f0) AND (#f1 = :f1)"),
+		ExpressionAttributeNames: map[string]*string{
+			"#f0": aws.String("Age"),
+			"#f1": aws.String("PK"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":f0": {N: aws.String("21")},
+			":f1": {S: aws.String("Person#foo")},
+		},
+	}, &dynamodb.ScanOutput{})
+	var got []Person
+	if err := client.Scan(&got).
+		Filter(dynago.Attr("Age").GreaterThanEqual(21)).
+		Filter(dynago.Attr("PK").Equal("foo")).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	ddb.done()
+}
+
+func TestConditionNested(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Team string `fmt:"Team#{}"`
+		Age  int64
+	}
+	p := Person{Name: "foo"}
+	ddb.MockDelete(&dynamodb.DeleteItemInput{
+		TableName: aws.String("foo"),
+		Key: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Person#foo")},
+		},
+		ConditionExpression: aws.String("#c0 IN (:c0, :c1) AND (#c1 BETWEEN :c2 AND :c3 OR NOT (begins_with(#c0, :c4))) AND size(#c2.#c3[0]) > :c5 AND contains(#c4, :c6) AND attribute_type(#c1, :c7) AND #c1 <> #c5"),
+		ExpressionAttributeNames: map[string]*string{
+			"#c0": aws.String("Team"),
+			"#c1": aws.String("Age"),
+			"#c2": aws.String("Address"),
+			"#c3": aws.String("Lines"),
+			"#c4": aws.String("Tags"),
+			"#c5": aws.String("OldAge"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":c0": {S: aws.String("Team#a")},
+			":c1": {S: aws.String("Team#b")},
+			":c2": {N: aws.String("18")},
+			":c3": {N: aws.String("65")},
+			":c4": {S: aws.String("Team#x")},
+			":c5": {N: aws.String("3")},
+			":c6": {S: aws.String("bar")},
+			":c7": {S: aws.String("N")},
+		},
+	})
+	cond := dynago.And(
+		dynago.Attr("Team").In("a", "b"),
+		dynago.Attr("Age").Between(18, 65).Or(dynago.Not(dynago.Attr("Team").BeginsWith("x"))),
+		dynago.Attr("Address.Lines[0]").Size().GreaterThan(3),
+		dynago.Attr("Tags").Contains("bar"),
+		dynago.Attr("Age").Type("N"),
+		dynago.Attr("Age").NotEqual(dynago.Attr("OldAge")),
+	)
+	if err := client.DeleteItem(&p).Condition(cond).Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	ddb.done()
+}
+
+func TestConditionDynamic(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Person struct {
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64
+	}
+	ddb.MockScan(&dynamodb.ScanInput{
+		TableName:        aws.String("foo"),
+		ConsistentRead:   aws.Bool(false),
+		FilterExpression: aws.String("#f0 >= :f0"),
+		ExpressionAttributeNames: map[string]*string{
+			"#f0": aws.String("Age"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":f0": {N: aws.String("21")},
+		},
+	}, &dynamodb.ScanOutput{})
+	var filters []*dynago.Condition
+	var name string
+	minAge := 21
+	if name != "" {
+		filters = append(filters, dynago.Attr("PK").Equal(name))
+	}
+	if minAge > 0 {
+		filters = append(filters, dynago.Attr("Age").GreaterThanEqual(minAge))
+	}
+	var got []Person
+	if err := client.Scan(&got).Filter(dynago.And(filters...)).Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	ddb.done()
+}
+
+func TestConditionQueryFilter(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Person struct {
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64
+	}
+	ddb.MockQuery(&dynamodb.QueryInput{
+		TableName:              aws.String("foo"),
+		ConsistentRead:         aws.Bool(false),
+		KeyConditionExpression: aws.String("#k0 = :k0"),
+		FilterExpression:       aws.String("#f0 < :f0"),
+		ExpressionAttributeNames: map[string]*string{
+			"#k0": aws.String("PK"),
+			"#f0": aws.String("Age"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":k0": {S: aws.String("Person#foo")},
+			":f0": {N: aws.String("30")},
+		},
+	}, &dynamodb.QueryOutput{})
+	var got []Person
+	if err := client.Query(&got).
+		KeyCondition(dynago.Key("PK").Equal("foo")).
+		Filter(dynago.Attr("Age").LessThan(30)).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	ddb.done()
+}
+
+func TestConditionTransaction(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64
+	}
+	p := Person{Name: "foo"}
+	ddb.MockTransactWriteItems(&dynamodb.TransactWriteItemsInput{
+		TransactItems: []*dynamodb.TransactWriteItem{{
+			ConditionCheck: &dynamodb.ConditionCheck{
+				TableName: aws.String("foo"),
+				Key: map[string]*dynamodb.AttributeValue{
+					"PK": {S: aws.String("Person#foo")},
+				},
+				ConditionExpression: aws.String("#c0 = :c0"),
+				ExpressionAttributeNames: map[string]*string{
+					"#c0": aws.String("Age"),
+				},
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":c0": {N: aws.String("33")},
+				},
+			},
+		}},
+	})
+	if err := client.TransactionWriteItems().
+		Items(client.ConditionCheck(&p).Condition(dynago.Attr("Age").Equal(33))).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	ddb.done()
+}
+
+func TestConditionInNoValues(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64
+	}
+	p := Person{Name: "foo"}
+	err := client.PutItem(&p).Condition(dynago.Attr("Age").In()).Exec()
+	if err == nil || !strings.Contains(err.Error(), "IN must have at least one value") {
+		t.Fatalf("expected err, got %v", err)
+	}
+	ddb.done()
+}
@@ -0,0 +1,131 @@
+package dynago
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// ErrInvalidCursor is returned when a cursor can not be decoded, or
+// when its signature, table or index does not match.
+var ErrInvalidCursor = errors.New("dynago: invalid cursor")
+
+// CursorConfig is used to sign cursors and bind them to the table and
+// index they came from.
+type CursorConfig struct {
+	// SigningKey is used to sign cursors with HMAC-SHA256. Cursors are
+	// neither signed nor verified if it is empty. Binding a cursor to
+	// a table or index only prevents tampering if it is signed.
+	SigningKey []byte
+
+	// TableName binds the cursor to a table.
+	TableName string
+
+	// IndexName binds the cursor to an index.
+	IndexName string
+}
+
+type cursorPayload struct {
+	Key   map[string]cursorAttr `json:"k"`
+	Table string                `json:"t,omitempty"`
+	Index string                `json:"i,omitempty"`
+}
+
+type cursorAttr struct {
+	S *string `json:"S,omitempty"`
+	N *string `json:"N,omitempty"`
+	B []byte  `json:"B,omitempty"`
+}
+
+// EncodeCursor encodes a LastEvaluatedKey into a URL-safe cursor. An
+// empty string is returned if the key is empty, meaning there are no
+// more pages. An optional config can be passed in second argument.
+func EncodeCursor(key map[string]*dynamodb.AttributeValue, config ...*CursorConfig) (string, error) {
+	if len(key) == 0 {
+		return "", nil
+	}
+	cfg := cursorConfig(config)
+	p := cursorPayload{
+		Key:   make(map[string]cursorAttr, len(key)),
+		Table: cfg.TableName,
+		Index: cfg.IndexName,
+	}
+	for name, av := range key {
+		if av == nil || (av.S == nil && av.N == nil && av.B == nil) {
+			return "", fmt.Errorf("dynago: key attribute %s must be of type S, N or B", name)
+		}
+		p.Key[name] = cursorAttr{S: av.S, N: av.N, B: av.B}
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		return "", fmt.Errorf("json.Marshal: %w", err)
+	}
+	cursor := base64.RawURLEncoding.EncodeToString(b)
+	if len(cfg.SigningKey) > 0 {
+		cursor += "." + base64.RawURLEncoding.EncodeToString(signCursor(cfg.SigningKey, cursor))
+	}
+	return cursor, nil
+}
+
+// DecodeCursor decodes a cursor created by EncodeCursor into an
+// ExclusiveStartKey. A nil key is returned if the cursor is empty. An
+// optional config can be passed in second argument. It must match
+// the config the cursor was encoded with, otherwise ErrInvalidCursor
+// is returned.
+func DecodeCursor(cursor string, config ...*CursorConfig) (map[string]*dynamodb.AttributeValue, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	cfg := cursorConfig(config)
+	payload, sig, signed := strings.Cut(cursor, ".")
+	if len(cfg.SigningKey) > 0 {
+		if !signed {
+			return nil, fmt.Errorf("%w: missing signature", ErrInvalidCursor)
+		}
+		b, err := base64.RawURLEncoding.DecodeString(sig)
+		if err != nil || !hmac.Equal(b, signCursor(cfg.SigningKey, payload)) {
+			return nil, fmt.Errorf("%w: signature mismatch", ErrInvalidCursor)
+		}
+	}
+	b, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCursor, err)
+	}
+	var p cursorPayload
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCursor, err)
+	}
+	if p.Table != cfg.TableName || p.Index != cfg.IndexName {
+		return nil, fmt.Errorf("%w: table or index mismatch", ErrInvalidCursor)
+	}
+	if len(p.Key) == 0 {
+		return nil, fmt.Errorf("%w: empty key", ErrInvalidCursor)
+	}
+	key := make(map[string]*dynamodb.AttributeValue, len(p.Key))
+	for name, attr := range p.Key {
+		if attr.S == nil && attr.N == nil && attr.B == nil {
+			return nil, fmt.Errorf("%w: key attribute %s has no value", ErrInvalidCursor, name)
+		}
+		key[name] = &dynamodb.AttributeValue{S: attr.S, N: attr.N, B: attr.B}
+	}
+	return key, nil
+}
+
+func cursorConfig(config []*CursorConfig) *CursorConfig {
+	if len(config) > 0 && config[0] != nil {
+		return config[0]
+	}
+	return &CursorConfig{}
+}
+
+func signCursor(key []byte, payload string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
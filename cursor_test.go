@@ -0,0 +1,119 @@
+package dynago_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/twharmon/dynago"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	key := map[string]*dynamodb.AttributeValue{
+		"PK": {S: aws.String("Person#foo/bar?baz")},
+		"SK": {N: aws.String("33")},
+		"B":  {B: []byte{0, 1, 2}},
+	}
+	cursor, err := dynago.EncodeCursor(key)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	if strings.ContainsAny(cursor, "+/=") {
+		t.Fatalf("cursor is not URL-safe: %s", cursor)
+	}
+	got, err := dynago.DecodeCursor(cursor)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, key, got)
+}
+
+func TestCursorEmpty(t *testing.T) {
+	cursor, err := dynago.EncodeCursor(nil)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, "", cursor)
+	got, err := dynago.DecodeCursor("")
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, map[string]*dynamodb.AttributeValue(nil), got)
+}
+
+func TestCursorSigned(t *testing.T) {
+	cfg := &dynago.CursorConfig{
+		SigningKey: []byte("secret"),
+		TableName:  "foo",
+		IndexName:  "bar",
+	}
+	key := map[string]*dynamodb.AttributeValue{
+		"PK": {S: aws.String("Person#foo")},
+	}
+	cursor, err := dynago.EncodeCursor(key, cfg)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	got, err := dynago.DecodeCursor(cursor, cfg)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, key, got)
+}
+
+func TestCursorTampered(t *testing.T) {
+	cfg := &dynago.CursorConfig{SigningKey: []byte("secret")}
+	cursor, err := dynago.EncodeCursor(map[string]*dynamodb.AttributeValue{
+		"PK": {S: aws.String("Person#foo")},
+	}, cfg)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	forged, err := dynago.EncodeCursor(map[string]*dynamodb.AttributeValue{
+		"PK": {S: aws.String("Person#bar")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	_, sig, _ := strings.Cut(cursor, ".")
+	for _, c := range []string{forged, forged + "." + sig} {
+		if _, err := dynago.DecodeCursor(c, cfg); !errors.Is(err, dynago.ErrInvalidCursor) {
+			t.Fatalf("want ErrInvalidCursor; got %v", err)
+		}
+	}
+	if _, err := dynago.DecodeCursor(cursor, &dynago.CursorConfig{SigningKey: []byte("other")}); !errors.Is(err, dynago.ErrInvalidCursor) {
+		t.Fatalf("want ErrInvalidCursor; got %v", err)
+	}
+}
+
+func TestCursorScopeMismatch(t *testing.T) {
+	key := map[string]*dynamodb.AttributeValue{
+		"PK": {S: aws.String("Person#foo")},
+	}
+	cursor, err := dynago.EncodeCursor(key, &dynago.CursorConfig{
+		SigningKey: []byte("secret"),
+		TableName:  "foo",
+		IndexName:  "bar",
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	_, err = dynago.DecodeCursor(cursor, &dynago.CursorConfig{
+		SigningKey: []byte("secret"),
+		TableName:  "foo",
+		IndexName:  "baz",
+	})
+	if !errors.Is(err, dynago.ErrInvalidCursor) {
+		t.Fatalf("want ErrInvalidCursor; got %v", err)
+	}
+}
+
+func TestCursorMalformed(t *testing.T) {
+	for _, c := range []string{"!!!", "e30", "bm90IGpzb24"} {
+		if _, err := dynago.DecodeCursor(c); !errors.Is(err, dynago.ErrInvalidCursor) {
+			t.Fatalf("want ErrInvalidCursor for %q; got %v", c, err)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package dynago
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"time"
@@ -12,10 +13,18 @@ import (
 type DeleteItem struct {
 	item   Keyer
 	input  *dynamodb.DeleteItemInput
+	output *DeleteItemOutput
+	values interface{}
+	ver    *version
 	dynago *Dynago
 	err    error
 }
 
+// DeleteItemOutput represents the output of a DeleteItem operation.
+type DeleteItemOutput struct {
+	ConsumedCapacity *dynamodb.ConsumedCapacity
+}
+
 // DeleteItem creates a DeleteItem operation.
 func (d *Dynago) DeleteItem(item Keyer) *DeleteItem {
 	return &DeleteItem{
@@ -56,6 +65,20 @@ func (q *DeleteItem) ConditionExpression(exp string) *DeleteItem {
 	return q
 }
 
+// Condition sets the ConditionExpression from the given condition.
+// Placeholders for attribute names and values are generated and
+// added to ExpressionAttributeNames and ExpressionAttributeValues.
+// Conditions of repeated calls are combined with AND.
+func (q *DeleteItem) Condition(c *Condition) *DeleteItem {
+	exp, err := q.dynago.condition(q.input.ConditionExpression, c, "c", itemType(q.item), &q.input.ExpressionAttributeNames, &q.input.ExpressionAttributeValues)
+	if err != nil {
+		q.err = fmt.Errorf("dynago: DeleteItem.Condition: %w", err)
+		return q
+	}
+	q.input.ConditionExpression = exp
+	return q
+}
+
 // ExpressionAttributeName sets a ExpressionAttributeName.
 func (q *DeleteItem) ExpressionAttributeName(name string, sub string) *DeleteItem {
 	if q.input.ExpressionAttributeNames == nil {
@@ -65,40 +88,105 @@ func (q *DeleteItem) ExpressionAttributeName(name string, sub string) *DeleteIte
 	return q
 }
 
+// ReturnValues sets ReturnValues. The returned attributes are
+// unmarshalled into the item, or into v if it is given.
+func (q *DeleteItem) ReturnValues(val string, v ...interface{}) *DeleteItem {
+	q.input.ReturnValues = &val
+	q.values = q.item
+	if len(v) > 0 {
+		q.values = v[0]
+	}
+	return q
+}
+
+// ReturnConsumedCapacity sets ReturnConsumedCapacity.
+func (q *DeleteItem) ReturnConsumedCapacity(val string) *DeleteItem {
+	q.input.ReturnConsumedCapacity = &val
+	return q
+}
+
+// Output sets the output to be populated when the operation is
+// executed.
+func (q *DeleteItem) Output(output *DeleteItemOutput) *DeleteItem {
+	q.output = output
+	return q
+}
+
 // Exec executes the operation.
 func (q *DeleteItem) Exec() error {
+	return q.ExecWithContext(context.Background())
+}
+
+// ExecWithContext executes the operation with the given context.
+func (q *DeleteItem) ExecWithContext(ctx context.Context) error {
 	if q.err != nil {
 		return q.err
 	}
-	var err error
-	q.input.Key, err = q.dynago.key(q.item)
+	input, ver, err := q.prepare()
 	if err != nil {
-		return fmt.Errorf("q.dynago.key: %w", err)
+		return err
 	}
-	_, err = q.dynago.ddb.DeleteItem(q.input)
+	output, err := q.dynago.ddb.DeleteItemWithContext(ctx, input)
 	if err != nil {
-		return fmt.Errorf("d.ddb.DeleteItem: %w", err)
+		return fmt.Errorf("d.ddb.DeleteItem: %w", versionError(mapError(err), ver))
+	}
+	if q.output != nil {
+		q.output.ConsumedCapacity = output.ConsumedCapacity
+	}
+	if q.values != nil && len(output.Attributes) > 0 {
+		if err := q.dynago.Unmarshal(output.Attributes, q.values); err != nil {
+			return fmt.Errorf("q.dynago.Unmarshal: %w", err)
+		}
 	}
 	return nil
 }
 
+// prepare returns the input of the operation with the key of the
+// item. If the item is versioned, the version condition is added.
+func (q *DeleteItem) prepare() (*dynamodb.DeleteItemInput, *version, error) {
+	input := *q.input
+	var err error
+	input.Key, err = q.dynago.key(q.item)
+	if err != nil {
+		return nil, nil, fmt.Errorf("q.dynago.key: %w", err)
+	}
+	ver, err := q.dynago.version(q.item)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ver != nil {
+		input.ExpressionAttributeNames = copyNames(input.ExpressionAttributeNames)
+		input.ExpressionAttributeValues = copyValues(input.ExpressionAttributeValues)
+		e := q.dynago.expression("v", nil, &input.ExpressionAttributeNames, &input.ExpressionAttributeValues)
+		input.ConditionExpression = andCondition(input.ConditionExpression, ver.condition(e))
+	}
+	return &input, ver, nil
+}
+
 // TransactionWriteItem implements the TransactionWriteItemer
 // interface.
 func (q *DeleteItem) TransactionWriteItem() (*dynamodb.TransactWriteItem, error) {
 	if q.err != nil {
 		return nil, q.err
 	}
-	key, err := q.dynago.key(q.item)
+	input, ver, err := q.prepare()
 	if err != nil {
 		return nil, err
 	}
+	q.ver = ver
 	return &dynamodb.TransactWriteItem{
 		Delete: &dynamodb.Delete{
-			Key:                       key,
-			TableName:                 q.input.TableName,
-			ConditionExpression:       q.input.ConditionExpression,
-			ExpressionAttributeNames:  q.input.ExpressionAttributeNames,
-			ExpressionAttributeValues: q.input.ExpressionAttributeValues,
+			Key:                       input.Key,
+			TableName:                 input.TableName,
+			ConditionExpression:       input.ConditionExpression,
+			ExpressionAttributeNames:  input.ExpressionAttributeNames,
+			ExpressionAttributeValues: input.ExpressionAttributeValues,
 		},
 	}, nil
 }
+
+func (q *DeleteItem) versioned() bool {
+	return q.ver != nil
+}
+
+func (q *DeleteItem) commit() {}
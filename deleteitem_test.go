@@ -173,3 +173,64 @@ func TestDeleteItemDefaultTableName(t *testing.T) {
 	}
 	ddb.done()
 }
+
+func TestDeleteItemReturnConsumedCapacity(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		*CompositeTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+	}
+	p := Person{Name: "foo"}
+	tableName := "bar"
+	cc := &dynamodb.ConsumedCapacity{TableName: &tableName, CapacityUnits: aws.Float64(1)}
+	ddb.MockDelete(&dynamodb.DeleteItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Person#foo")},
+		},
+		TableName:              &tableName,
+		ReturnConsumedCapacity: aws.String(dynamodb.ReturnConsumedCapacityTotal),
+	}, &dynamodb.DeleteItemOutput{ConsumedCapacity: cc})
+	var output dynago.DeleteItemOutput
+	if err := client.DeleteItem(&p).
+		TableName(tableName).
+		ReturnConsumedCapacity(dynamodb.ReturnConsumedCapacityTotal).
+		Output(&output).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, cc, output.ConsumedCapacity)
+	ddb.done()
+}
+
+func TestDeleteItemReturnValues(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		*CompositeTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64
+	}
+	p := Person{Name: "foo"}
+	tableName := "bar"
+	ddb.MockDelete(&dynamodb.DeleteItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Person#foo")},
+		},
+		TableName:    &tableName,
+		ReturnValues: aws.String(dynamodb.ReturnValueAllOld),
+	}, &dynamodb.DeleteItemOutput{
+		Attributes: map[string]*dynamodb.AttributeValue{
+			"PK":  {S: aws.String("Person#foo")},
+			"Age": {N: aws.String("33")},
+		},
+	})
+	if err := client.DeleteItem(&p).
+		TableName(tableName).
+		ReturnValues(dynamodb.ReturnValueAllOld).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, Person{Name: "foo", Age: 33}, p)
+	ddb.done()
+}
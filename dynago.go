@@ -4,10 +4,10 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
-	"github.com/twharmon/slices"
 )
 
 // DynagoAPI provides an interface to enable mocking the
@@ -17,11 +17,14 @@ type DynagoAPI interface {
 	DeleteItem(Keyer) *DeleteItem
 	PutItem(Keyer) *PutItem
 	GetItem(Keyer) *GetItem
+	BatchGetItem(...Keyer) *BatchGetItem
+	BatchWriteItem() *BatchWriteItem
 	Query(interface{}) *Query
 	Scan(interface{}) *Scan
 	UpdateItem(Keyer) *UpdateItem
 	ConditionCheck(Keyer) *ConditionCheck
 	TransactionWriteItems() *TransactionWriteItems
+	TransactionGetItems() *TransactionGetItems
 	Marshal(interface{}) (map[string]*dynamodb.AttributeValue, error)
 	Unmarshal(map[string]*dynamodb.AttributeValue, interface{}) error
 }
@@ -32,19 +35,31 @@ type Keyer interface {
 	PrimaryKeys() []string
 }
 
+var keyerType = reflect.TypeOf((*Keyer)(nil)).Elem()
+
 // Dynago provides the API operation methods for making requests to
 // Amazon DynamoDB. Dynago methods are safe to use concurrently.
 type Dynago struct {
-	config   *Config
-	cache    map[string]map[int]*field
-	cacheMtx sync.Mutex
-	ddb      dynamodbiface.DynamoDBAPI
+	config *Config
+	codecs sync.Map // reflect.Type to *codec
+	ddb    dynamodbiface.DynamoDBAPI
 }
 
 // Config is used to customize struct tag names.
 type Config struct {
 	// AttrTagName specifies which tag is used for a DynamoDB
-	// item attribute name. Defaults to "attr".
+	// item attribute name. Defaults to "attr". The name may be
+	// followed by comma separated options:
+	//
+	//   - omitempty: the attribute is not written if the field is
+	//     false, 0, a nil pointer, an empty string, slice or map, or
+	//     a zero time.Time.
+	//   - keepempty: the attribute is written even if the field is
+	//     empty, overriding DefaultOmitEmpty.
+	//   - null: the attribute is written as NULL if the field is a
+	//     nil pointer, unless omitempty is set.
+	//   - omitnull: the attribute is not written if the field is a
+	//     nil pointer, overriding NullPointers.
 	AttrTagName string
 
 	// FmtTagName specifies which tag is used to format the attribute
@@ -64,6 +79,40 @@ type Config struct {
 	// "copy".
 	AttrsToCopyTagName string
 
+	// VersionTagName specifies which tag marks the integer field used
+	// for optimistic locking. PutItem, UpdateItem and DeleteItem
+	// operations on items with a version field are conditioned on the
+	// version in DynamoDB being the version of the item, and PutItem
+	// and UpdateItem increment it. Defaults to "version".
+	VersionTagName string
+
+	// AutoTagName specifies which tag marks time.Time fields that are
+	// set automatically. Fields tagged "create" are set by PutItem if
+	// they are zero. Fields tagged "update" are set by PutItem and
+	// UpdateItem. Defaults to "auto".
+	AutoTagName string
+
+	// TTLTagName specifies which tag marks the time.Time field used as
+	// the TTL attribute of the table. It is stored as a Number in
+	// epoch seconds, and omitted if it is zero. Defaults to "ttl".
+	TTLTagName string
+
+	// TTLAttrName is the name of the TTL attribute of items that have
+	// no field with the TTL tag. Defaults to "TTL".
+	TTLAttrName string
+
+	// Now returns the time automatic timestamps are set to, and that
+	// TTL attributes are compared to. Defaults to time.Now.
+	Now func() time.Time
+
+	// DefaultOmitEmpty sets the omitempty option of all fields
+	// without the keepempty option.
+	DefaultOmitEmpty bool
+
+	// NullPointers sets nil pointer fields without the omitnull
+	// option to be written as NULL. By default they are not written.
+	NullPointers bool
+
 	// AdditionalAttrs can be added for each dynamodb item.
 	AdditionalAttrs func(map[string]*dynamodb.AttributeValue, reflect.Value)
 
@@ -73,13 +122,17 @@ type Config struct {
 
 	// DefaultConsistentRead is the default read consistency model.
 	DefaultConsistentRead bool
+
+	// MaxBatchRetries is the maximum number of times unprocessed
+	// keys or items of batch operations are retried. Defaults to 5.
+	// A negative value disables retries.
+	MaxBatchRetries int
 }
 
 // New creates a new Dynago client. An optional config can be passed
 // in second argument.
 func New(ddb dynamodbiface.DynamoDBAPI, config ...*Config) *Dynago {
 	d := Dynago{
-		cache:  make(map[string]map[int]*field),
 		config: &Config{},
 	}
 	if len(config) > 0 {
@@ -100,54 +153,62 @@ func New(ddb dynamodbiface.DynamoDBAPI, config ...*Config) *Dynago {
 	if d.config.LayoutTagName == "" {
 		d.config.LayoutTagName = "layout"
 	}
+	if d.config.VersionTagName == "" {
+		d.config.VersionTagName = "version"
+	}
+	if d.config.AutoTagName == "" {
+		d.config.AutoTagName = "auto"
+	}
+	if d.config.TTLTagName == "" {
+		d.config.TTLTagName = "ttl"
+	}
+	if d.config.TTLAttrName == "" {
+		d.config.TTLAttrName = "TTL"
+	}
+	if d.config.Now == nil {
+		d.config.Now = time.Now
+	}
+	if d.config.MaxBatchRetries == 0 {
+		d.config.MaxBatchRetries = 5
+	}
 	d.ddb = ddb
 	return &d
 }
 
 // Unmarshal converts a DynamoDB item into a Go struct.
 func (d *Dynago) Unmarshal(item map[string]*dynamodb.AttributeValue, v interface{}) error {
+	if u, ok := v.(ItemUnmarshaler); ok && d.useGenerated(v) {
+		return u.UnmarshalDynago(item)
+	}
 	ty, val := tyVal(v)
-	cache, err := d.cachedStruct(ty)
+	c, err := d.codec(ty)
 	if err != nil {
-		return fmt.Errorf("d.cachedStruct: %w", err)
+		return fmt.Errorf("d.codec: %w", err)
 	}
-	for i := 0; i < ty.NumField(); i++ {
-		if cache[i].attrName == "-" || ty.Field(i).Anonymous {
-			continue
-		}
-		if err := cache[i].unmarshal(item, val); err != nil {
-			return err
-		}
-	}
-	return nil
+	return c.unmarshal(item, val)
 }
 
 // Marshal converts a Go struct into a DynamoDB item.
 func (d *Dynago) Marshal(v interface{}) (map[string]*dynamodb.AttributeValue, error) {
-	m := make(map[string]*dynamodb.AttributeValue)
 	ty, val := tyVal(v)
-	cache, err := d.cachedStruct(ty)
-	if err != nil {
-		return nil, fmt.Errorf("d.cachedStruct: %w", err)
-	}
-	_, isTopLevel := v.(Keyer)
-	for i := 0; i < ty.NumField(); i++ {
-		if cache[i].attrName == "-" {
-			continue
-		}
-		attrVal, err := cache[i].attrVal(val)
+	var m map[string]*dynamodb.AttributeValue
+	if gen, ok := v.(ItemMarshaler); ok && d.useGenerated(v) {
+		var err error
+		m, err = gen.MarshalDynago()
 		if err != nil {
-			return nil, fmt.Errorf("cache.attrVal: %w", err)
+			return nil, fmt.Errorf("MarshalDynago: %w", err)
 		}
-		if attrVal == nil {
-			continue
+	} else {
+		c, err := d.codec(ty)
+		if err != nil {
+			return nil, fmt.Errorf("d.codec: %w", err)
 		}
-		m[cache[i].attrName] = attrVal
-		for _, cp := range cache[i].attrsToCopy {
-			m[cp] = attrVal
+		m, err = c.marshal(val)
+		if err != nil {
+			return nil, err
 		}
 	}
-	if isTopLevel && d.config.AdditionalAttrs != nil {
+	if _, isTopLevel := v.(Keyer); isTopLevel && d.config.AdditionalAttrs != nil {
 		d.config.AdditionalAttrs(m, val)
 	}
 	return m, nil
@@ -156,20 +217,18 @@ func (d *Dynago) Marshal(v interface{}) (map[string]*dynamodb.AttributeValue, er
 func (d *Dynago) key(v Keyer) (map[string]*dynamodb.AttributeValue, error) {
 	m := make(map[string]*dynamodb.AttributeValue)
 	ty, val := tyVal(v)
-	cache, err := d.cachedStruct(ty)
+	c, err := d.codec(ty)
 	if err != nil {
-		return nil, fmt.Errorf("d.cachedStruct: %w", err)
+		return nil, fmt.Errorf("d.codec: %w", err)
 	}
 	primKeys := v.PrimaryKeys()
 	for _, primKey := range primKeys {
-		for i := 0; i < ty.NumField(); i++ {
-			if cache[i].attrName == primKey || slices.Contains(cache[i].attrsToCopy, primKey) {
-				av, err := cache[i].attrVal(val)
-				if err != nil {
-					return nil, fmt.Errorf("cache.attrVal: %w", err)
-				}
-				m[primKey] = av
+		if f := c.byAttr[primKey]; f != nil {
+			av, err := f.attrVal(val)
+			if err != nil {
+				return nil, fmt.Errorf("f.attrVal: %w", err)
 			}
+			m[primKey] = av
 		}
 	}
 	if d.config.AdditionalAttrs != nil {
@@ -189,20 +248,3 @@ func (d *Dynago) key(v Keyer) (map[string]*dynamodb.AttributeValue, error) {
 	}
 	return m, nil
 }
-
-func (d *Dynago) cachedStruct(ty reflect.Type) (map[int]*field, error) {
-	key := ty.String()
-	d.cacheMtx.Lock()
-	defer d.cacheMtx.Unlock()
-	if d.cache[key] == nil {
-		d.cache[key] = make(map[int]*field)
-		for i := 0; i < ty.NumField(); i++ {
-			cfg, err := d.field(ty.Field(i), i)
-			if err != nil {
-				return nil, fmt.Errorf("d.field")
-			}
-			d.cache[key][i] = cfg
-		}
-	}
-	return d.cache[key], nil
-}
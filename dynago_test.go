@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -1624,3 +1626,636 @@ func TestMarshalWithUnexportedMember(t *testing.T) {
 	}
 	assertEq(t, want, got)
 }
+
+func TestMarshalTimeUnixMilli(t *testing.T) {
+	type Event struct {
+		At time.Time `layout:"unixmilli"`
+	}
+	e := Event{At: time.Date(2023, 4, 5, 6, 7, 8, 9000000, time.UTC)}
+	client := dynago.New(nil)
+	got, err := client.Marshal(&e)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, map[string]*dynamodb.AttributeValue{
+		"At": {N: aws.String("1680674828009")},
+	}, got)
+}
+
+func TestUnmarshalTimeUnixMilli(t *testing.T) {
+	type Event struct {
+		At time.Time `layout:"unixmilli"`
+	}
+	client := dynago.New(nil)
+	var got Event
+	if err := client.Unmarshal(map[string]*dynamodb.AttributeValue{
+		"At": {N: aws.String("1680674828009")},
+	}, &got); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, Event{At: time.Date(2023, 4, 5, 6, 7, 8, 9000000, time.UTC)}, got)
+}
+
+func TestMarshalTimeTypeN(t *testing.T) {
+	type Event struct {
+		At *time.Time `type:"N"`
+	}
+	at := time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC)
+	client := dynago.New(nil)
+	got, err := client.Marshal(&Event{At: &at})
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, map[string]*dynamodb.AttributeValue{
+		"At": {N: aws.String("1680674828")},
+	}, got)
+}
+
+func TestMarshalTimeTypeNInvalidLayout(t *testing.T) {
+	type Event struct {
+		At time.Time `type:"N" layout:"2006-01-02"`
+	}
+	client := dynago.New(nil)
+	if _, err := client.Marshal(&Event{}); err == nil {
+		t.Fatalf("expected err")
+	}
+}
+
+func TestUnmarshalTimeNumberMismatch(t *testing.T) {
+	type Event struct {
+		At  time.Time
+		Day *time.Time `layout:"2006-01-02"`
+	}
+	client := dynago.New(nil)
+	for _, item := range []map[string]*dynamodb.AttributeValue{
+		{"At": {N: aws.String("1680674828")}},
+		{"Day": {N: aws.String("1680674828")}},
+	} {
+		var got Event
+		err := client.Unmarshal(item, &got)
+		if err == nil || !strings.Contains(err.Error(), "type mismatch") {
+			t.Fatalf("expected type mismatch err, got %v", err)
+		}
+	}
+}
+
+func TestUnmarshalTimeNumberUnixLayout(t *testing.T) {
+	type Event struct {
+		At time.Time `layout:"unix"`
+	}
+	client := dynago.New(nil)
+	var got Event
+	if err := client.Unmarshal(map[string]*dynamodb.AttributeValue{
+		"At": {N: aws.String("1680674828")},
+	}, &got); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, Event{At: time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC)}, got)
+}
+
+func TestMarshalTimeUnixNanoFmt(t *testing.T) {
+	type Event struct {
+		*SimpleTable
+		ID string    `attr:"PK" fmt:"Event#{}#{At}"`
+		At time.Time `layout:"unixnano" attr:"-"`
+	}
+	e := Event{ID: "foo", At: time.Date(2023, 4, 5, 6, 7, 8, 9, time.UTC)}
+	client := dynago.New(nil)
+	got, err := client.Marshal(&e)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, map[string]*dynamodb.AttributeValue{
+		"PK": {S: aws.String("Event#foo#1680674828000000009")},
+	}, got)
+	var back Event
+	if err := client.Unmarshal(got, &back); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, e, back)
+}
+
+func TestMarshalSliceTimeUnix(t *testing.T) {
+	type Person struct {
+		Appointments []time.Time `layout:"unix"`
+	}
+	p := Person{
+		Appointments: []time.Time{time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC)},
+	}
+	client := dynago.New(nil)
+	got, err := client.Marshal(&p)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	want := map[string]*dynamodb.AttributeValue{
+		"Appointments": {L: []*dynamodb.AttributeValue{{N: aws.String("1680674828")}}},
+	}
+	assertEq(t, want, got)
+	var back Person
+	if err := client.Unmarshal(got, &back); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, p, back)
+}
+
+func TestMarshalOmitEmpty(t *testing.T) {
+	type Person struct {
+		Name    string    `attr:",omitempty"`
+		Age     int64     `attr:"age,omitempty"`
+		Admin   bool      `attr:",omitempty"`
+		Tags    []string  `attr:",omitempty"`
+		Born    time.Time `attr:",omitempty"`
+		Email   *string   `attr:",omitempty"`
+		Friends int64
+	}
+	client := dynago.New(nil)
+	got, err := client.Marshal(&Person{Email: aws.String("")})
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, map[string]*dynamodb.AttributeValue{
+		"Email":   {S: aws.String("")},
+		"Friends": {N: aws.String("0")},
+	}, got)
+}
+
+func TestMarshalDefaultOmitEmpty(t *testing.T) {
+	type Person struct {
+		Name    string
+		Friends int64 `attr:",keepempty"`
+	}
+	client := dynago.New(nil, &dynago.Config{DefaultOmitEmpty: true})
+	got, err := client.Marshal(&Person{})
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, map[string]*dynamodb.AttributeValue{
+		"Friends": {N: aws.String("0")},
+	}, got)
+}
+
+func TestMarshalNilPointer(t *testing.T) {
+	type Person struct {
+		Name  *string `fmt:"Person#{}"`
+		Age   *int64
+		Email *string `attr:",null"`
+	}
+	client := dynago.New(nil)
+	got, err := client.Marshal(&Person{})
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, map[string]*dynamodb.AttributeValue{
+		"Email": {NULL: aws.Bool(true)},
+	}, got)
+}
+
+func TestMarshalNullPointers(t *testing.T) {
+	type Person struct {
+		Name  *string `attr:",omitnull"`
+		Age   *int64
+		Email *string `attr:",omitempty"`
+	}
+	client := dynago.New(nil, &dynago.Config{NullPointers: true})
+	got, err := client.Marshal(&Person{})
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, map[string]*dynamodb.AttributeValue{
+		"Age": {NULL: aws.Bool(true)},
+	}, got)
+}
+
+func TestUnmarshalNilPointer(t *testing.T) {
+	type Person struct {
+		Name  *string
+		Age   *int64
+		Email *string
+	}
+	client := dynago.New(nil)
+	got := Person{Email: aws.String("foo")}
+	if err := client.Unmarshal(map[string]*dynamodb.AttributeValue{
+		"Email": {NULL: aws.Bool(true)},
+	}, &got); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, Person{}, got)
+}
+
+func TestMarshalUnknownAttrOption(t *testing.T) {
+	type Person struct {
+		Name string `attr:",foo"`
+	}
+	client := dynago.New(nil)
+	if _, err := client.Marshal(&Person{}); err == nil {
+		t.Fatalf("expected err")
+	}
+}
+
+type Color string
+
+func TestMarshalSetTypes(t *testing.T) {
+	type Person struct {
+		Colors []Color              `type:"SS"`
+		Lucky  []int64              `type:"NS"`
+		Keys   [][]byte             `type:"BS"`
+		Tags   map[string]struct{}  `type:"SS"`
+		Empty  []string             `type:"SS"`
+		Scores map[float64]struct{} `type:"NS"`
+	}
+	p := Person{
+		Colors: []Color{"red", "blue", "red"},
+		Lucky:  []int64{7, 13},
+		Keys:   [][]byte{{1}, {2}},
+		Tags:   map[string]struct{}{"foo": {}, "bar": {}},
+		Empty:  []string{},
+	}
+	want := map[string]*dynamodb.AttributeValue{
+		"Colors": {SS: []*string{aws.String("red"), aws.String("blue")}},
+		"Lucky":  {NS: []*string{aws.String("7"), aws.String("13")}},
+		"Keys":   {BS: [][]byte{{1}, {2}}},
+		"Tags":   {SS: []*string{aws.String("bar"), aws.String("foo")}},
+	}
+	client := dynago.New(nil)
+	got, err := client.Marshal(&p)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, want, got)
+}
+
+func TestMarshalSetMapWithoutType(t *testing.T) {
+	type Person struct {
+		Tags map[string]struct{}
+	}
+	p := Person{Tags: map[string]struct{}{"a": {}}}
+	client := dynago.New(nil)
+	got, err := client.Marshal(&p)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	if got["Tags"].M == nil {
+		t.Fatalf("expected M, got %s", got["Tags"])
+	}
+	var back Person
+	if err := client.Unmarshal(got, &back); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, p, back)
+}
+
+func TestUnmarshalSetMapFromMap(t *testing.T) {
+	type Untyped struct {
+		Tags map[string]struct{}
+	}
+	type Person struct {
+		Tags map[string]struct{} `type:"SS"`
+	}
+	client := dynago.New(nil)
+	item, err := client.Marshal(&Untyped{Tags: map[string]struct{}{"a": {}}})
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	var got Person
+	if err := client.Unmarshal(item, &got); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, Person{Tags: map[string]struct{}{"a": {}}}, got)
+}
+
+func TestUnmarshalSetTypes(t *testing.T) {
+	type Person struct {
+		Colors []Color              `type:"SS"`
+		Lucky  []int64              `type:"NS"`
+		Keys   [][]byte             `type:"BS"`
+		Tags   map[string]struct{}  `type:"SS"`
+		Scores map[float64]struct{} `type:"NS"`
+	}
+	item := map[string]*dynamodb.AttributeValue{
+		"Colors": {SS: []*string{aws.String("red"), aws.String("blue")}},
+		"Lucky":  {NS: []*string{aws.String("7"), aws.String("13")}},
+		"Keys":   {BS: [][]byte{{1}, {2}}},
+		"Tags":   {SS: []*string{aws.String("bar"), aws.String("foo")}},
+		"Scores": {NS: []*string{aws.String("1.5")}},
+	}
+	want := Person{
+		Colors: []Color{"red", "blue"},
+		Lucky:  []int64{7, 13},
+		Keys:   [][]byte{{1}, {2}},
+		Tags:   map[string]struct{}{"foo": {}, "bar": {}},
+		Scores: map[float64]struct{}{1.5: {}},
+	}
+	client := dynago.New(nil)
+	var got Person
+	if err := client.Unmarshal(item, &got); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, want, got)
+}
+
+func TestMarshalSetInvalidType(t *testing.T) {
+	type Person struct {
+		Tags string `type:"SS"`
+	}
+	client := dynago.New(nil)
+	if _, err := client.Marshal(&Person{}); err == nil {
+		t.Fatalf("expected err")
+	}
+}
+
+func TestMarshalSetInvalidElement(t *testing.T) {
+	type Person struct {
+		Lucky []string `type:"NS"`
+	}
+	client := dynago.New(nil)
+	if _, err := client.Marshal(&Person{Lucky: []string{"a"}}); err == nil {
+		t.Fatalf("expected err")
+	}
+}
+
+type BaseEntity struct {
+	ID      string    `attr:"PK" fmt:"{Kind}#{}"`
+	Kind    string    `attr:"-"`
+	Created time.Time `layout:"2006-01-02" copy:"GSISK"`
+}
+
+type Audit struct {
+	UpdatedBy string
+}
+
+func TestMarshalEmbedded(t *testing.T) {
+	type Person struct {
+		*SimpleTable
+		BaseEntity
+		*Audit
+		Name string `attr:"SK" fmt:"Person#{}#{ID}"`
+	}
+	p := Person{
+		BaseEntity: BaseEntity{ID: "foo", Kind: "Person", Created: time.Date(2023, 4, 5, 0, 0, 0, 0, time.UTC)},
+		Audit:      &Audit{UpdatedBy: "bar"},
+		Name:       "baz",
+	}
+	want := map[string]*dynamodb.AttributeValue{
+		"PK":        {S: aws.String("Person#foo")},
+		"SK":        {S: aws.String("Person#baz#foo")},
+		"Created":   {S: aws.String("2023-04-05")},
+		"GSISK":     {S: aws.String("2023-04-05")},
+		"UpdatedBy": {S: aws.String("bar")},
+	}
+	client := dynago.New(nil)
+	got, err := client.Marshal(&p)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, want, got)
+}
+
+func TestMarshalEmbeddedNilPointer(t *testing.T) {
+	type Person struct {
+		*Audit
+		Name string
+	}
+	client := dynago.New(nil)
+	got, err := client.Marshal(&Person{Name: "foo"})
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, map[string]*dynamodb.AttributeValue{
+		"Name": {S: aws.String("foo")},
+	}, got)
+}
+
+func TestUnmarshalEmbedded(t *testing.T) {
+	type Person struct {
+		*SimpleTable
+		BaseEntity
+		*Audit
+		Name string `attr:"SK" fmt:"Person#{}"`
+	}
+	item := map[string]*dynamodb.AttributeValue{
+		"PK":        {S: aws.String("Person#foo")},
+		"SK":        {S: aws.String("Person#baz")},
+		"Created":   {S: aws.String("2023-04-05")},
+		"UpdatedBy": {S: aws.String("bar")},
+	}
+	want := Person{
+		BaseEntity: BaseEntity{ID: "foo", Kind: "Person", Created: time.Date(2023, 4, 5, 0, 0, 0, 0, time.UTC)},
+		Audit:      &Audit{UpdatedBy: "bar"},
+		Name:       "baz",
+	}
+	client := dynago.New(nil)
+	var got Person
+	if err := client.Unmarshal(item, &got); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, want, got)
+}
+
+func TestMarshalEmbeddedConflict(t *testing.T) {
+	type Owner struct {
+		Name  string
+		Email string
+	}
+	type Editor struct {
+		Name  string
+		Email string `attr:"Email"`
+	}
+	type Post struct {
+		Owner
+		Editor
+		Title string `attr:"Name"`
+	}
+	p := Post{
+		Owner:  Owner{Name: "owner", Email: "owner@example.com"},
+		Editor: Editor{Name: "editor", Email: "editor@example.com"},
+		Title:  "title",
+	}
+	want := map[string]*dynamodb.AttributeValue{
+		"Name":  {S: aws.String("title")},
+		"Email": {S: aws.String("editor@example.com")},
+	}
+	client := dynago.New(nil)
+	got, err := client.Marshal(&p)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, want, got)
+}
+
+func TestMarshalEmbeddedNamed(t *testing.T) {
+	type Person struct {
+		Audit `attr:"Audit"`
+		Name  string
+	}
+	p := Person{Audit: Audit{UpdatedBy: "bar"}, Name: "foo"}
+	want := map[string]*dynamodb.AttributeValue{
+		"Name": {S: aws.String("foo")},
+		"Audit": {M: map[string]*dynamodb.AttributeValue{
+			"UpdatedBy": {S: aws.String("bar")},
+		}},
+	}
+	client := dynago.New(nil)
+	got, err := client.Marshal(&p)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, want, got)
+}
+
+func TestMarshalSameTypeNames(t *testing.T) {
+	client := dynago.New(nil)
+	first := func() map[string]*dynamodb.AttributeValue {
+		type Item struct {
+			A string
+		}
+		item, err := client.Marshal(&Item{A: "a"})
+		if err != nil {
+			t.Fatalf("unexpected err: %s", err)
+		}
+		return item
+	}
+	second := func() map[string]*dynamodb.AttributeValue {
+		type Item struct {
+			B string
+		}
+		item, err := client.Marshal(&Item{B: "b"})
+		if err != nil {
+			t.Fatalf("unexpected err: %s", err)
+		}
+		return item
+	}
+	assertEq(t, map[string]*dynamodb.AttributeValue{"A": {S: aws.String("a")}}, first())
+	assertEq(t, map[string]*dynamodb.AttributeValue{"B": {S: aws.String("b")}}, second())
+}
+
+func TestMarshalConcurrent(t *testing.T) {
+	client := dynago.New(nil)
+	type Item struct {
+		PK  string `fmt:"Item#{}"`
+		Age int
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			item, err := client.Marshal(&Item{PK: "foo", Age: i})
+			if err != nil {
+				t.Errorf("unexpected err: %s", err)
+				return
+			}
+			var got Item
+			if err := client.Unmarshal(item, &got); err != nil {
+				t.Errorf("unexpected err: %s", err)
+				return
+			}
+			if got.PK != "foo" || got.Age != i {
+				t.Errorf("unexpected item %+v", got)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+type generatedItem struct {
+	*SimpleTable
+	Name string
+}
+
+func (g generatedItem) MarshalDynago() (map[string]*dynamodb.AttributeValue, error) {
+	return map[string]*dynamodb.AttributeValue{"Generated": {S: aws.String(g.Name)}}, nil
+}
+
+func (g *generatedItem) UnmarshalDynago(item map[string]*dynamodb.AttributeValue) error {
+	g.Name = "generated " + *item["Generated"].S
+	return nil
+}
+
+func TestMarshalItemMarshaler(t *testing.T) {
+	client := dynago.New(nil)
+	got, err := client.Marshal(&generatedItem{Name: "foo"})
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, map[string]*dynamodb.AttributeValue{"Generated": {S: aws.String("foo")}}, got)
+	var item generatedItem
+	if err := client.Unmarshal(got, &item); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, "generated foo", item.Name)
+}
+
+type generatedConfigItem struct {
+	generatedItem
+}
+
+func (*generatedConfigItem) DynagoConfig() dynago.GeneratedConfig {
+	return dynago.GeneratedConfig{
+		Type:               reflect.TypeOf(generatedConfigItem{}),
+		AttrTagName:        "attr",
+		FmtTagName:         "fmt",
+		TypeTagName:        "type",
+		LayoutTagName:      "layout",
+		AttrsToCopyTagName: "copy",
+		VersionTagName:     "version",
+		AutoTagName:        "auto",
+		TTLTagName:         "ttl",
+	}
+}
+
+func TestMarshalGeneratedConfig(t *testing.T) {
+	client := dynago.New(nil)
+	got, err := client.Marshal(&generatedConfigItem{generatedItem{Name: "foo"}})
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, map[string]*dynamodb.AttributeValue{"Generated": {S: aws.String("foo")}}, got)
+}
+
+func TestMarshalGeneratedPromoted(t *testing.T) {
+	type embeds struct {
+		generatedConfigItem
+		Age int
+	}
+	type wrapper struct {
+		generatedConfigItem
+	}
+	type embedsNil struct {
+		*wrapper
+		Age int
+	}
+	client := dynago.New(nil)
+	got, err := client.Marshal(&embeds{generatedConfigItem{generatedItem{Name: "foo"}}, 3})
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, map[string]*dynamodb.AttributeValue{
+		"Name": {S: aws.String("foo")},
+		"Age":  {N: aws.String("3")},
+	}, got)
+	got, err = client.Marshal(&embedsNil{Age: 3})
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, map[string]*dynamodb.AttributeValue{"Age": {N: aws.String("3")}}, got)
+}
+
+func TestMarshalGeneratedConfigMismatch(t *testing.T) {
+	for _, config := range []*dynago.Config{
+		{AttrTagName: "dynamo"},
+		{DefaultOmitEmpty: true},
+		{NullPointers: true},
+	} {
+		client := dynago.New(nil, config)
+		got, err := client.Marshal(&generatedConfigItem{generatedItem{Name: "foo"}})
+		if err != nil {
+			t.Fatalf("unexpected err: %s", err)
+		}
+		assertEq(t, map[string]*dynamodb.AttributeValue{"Name": {S: aws.String("foo")}}, got)
+		var item generatedConfigItem
+		if err := client.Unmarshal(got, &item); err != nil {
+			t.Fatalf("unexpected err: %s", err)
+		}
+		assertEq(t, "foo", item.Name)
+	}
+}
@@ -0,0 +1,184 @@
+package dynago
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+var (
+	// ErrConditionalCheckFailed is returned when a condition
+	// expression evaluates to false.
+	ErrConditionalCheckFailed = errors.New("dynago: conditional check failed")
+
+	// ErrThrottled is returned when a request is throttled because
+	// provisioned throughput or an account limit is exceeded.
+	ErrThrottled = errors.New("dynago: request throttled")
+
+	// ErrTransactionConflict is returned when a request conflicts
+	// with an ongoing transaction.
+	ErrTransactionConflict = errors.New("dynago: transaction conflict")
+
+	// ErrTransactionCanceled is returned when a transaction is
+	// canceled.
+	ErrTransactionCanceled = errors.New("dynago: transaction canceled")
+
+	// ErrVersionConflict is returned when a write fails because the
+	// version of the item in DynamoDB does not match the version of
+	// the item written. Since the version condition is combined with
+	// other conditions of the operation, it may also be returned when
+	// one of those fails.
+	ErrVersionConflict = errors.New("dynago: version conflict")
+)
+
+// awsError wraps an error returned by DynamoDB so that it matches one
+// of the sentinel errors of this package with errors.Is.
+type awsError struct {
+	err    error
+	target error
+}
+
+// Error implements the error interface.
+func (e *awsError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the error returned by DynamoDB.
+func (e *awsError) Unwrap() error {
+	return e.err
+}
+
+// Is reports whether the target is the sentinel error matching the
+// error code.
+func (e *awsError) Is(target error) bool {
+	return target == e.target
+}
+
+// mapError maps error codes returned by DynamoDB to the sentinel
+// errors of this package.
+func mapError(err error) error {
+	var aerr awserr.Error
+	if !errors.As(err, &aerr) {
+		return err
+	}
+	var target error
+	switch aerr.Code() {
+	case dynamodb.ErrCodeConditionalCheckFailedException:
+		target = ErrConditionalCheckFailed
+	case dynamodb.ErrCodeProvisionedThroughputExceededException, dynamodb.ErrCodeRequestLimitExceeded, "ThrottlingException":
+		target = ErrThrottled
+	case dynamodb.ErrCodeTransactionConflictException:
+		target = ErrTransactionConflict
+	case dynamodb.ErrCodeTransactionCanceledException:
+		target = ErrTransactionCanceled
+	default:
+		return err
+	}
+	return &awsError{err: err, target: target}
+}
+
+// TransactionCanceledError is returned when a TransactionWriteItems
+// operation is canceled. It matches ErrTransactionCanceled with
+// errors.Is, as well as ErrConditionalCheckFailed,
+// ErrTransactionConflict and ErrThrottled if any of the reasons has
+// the corresponding code. It matches ErrVersionConflict if the
+// condition of a versioned item failed.
+type TransactionCanceledError struct {
+	// Reasons holds the reasons of the items that caused the
+	// transaction to be canceled.
+	Reasons []*TransactionCancellationReason
+
+	err error
+}
+
+// TransactionCancellationReason describes why an item caused a
+// transaction to be canceled.
+type TransactionCancellationReason struct {
+	// Index is the position of the item in the transaction.
+	Index int
+
+	// Item is the item that caused the transaction to be canceled.
+	Item TransactionWriteItemer
+
+	// Code is the cancellation reason code, such as
+	// ConditionalCheckFailed or TransactionConflict.
+	Code string
+
+	// Message is the cancellation reason message.
+	Message string
+}
+
+// Error implements the error interface.
+func (e *TransactionCanceledError) Error() string {
+	reasons := make([]string, len(e.Reasons))
+	for i, r := range e.Reasons {
+		reasons[i] = fmt.Sprintf("item %d: %s", r.Index, r.Code)
+		if r.Message != "" {
+			reasons[i] += ": " + r.Message
+		}
+	}
+	return fmt.Sprintf("dynago: transaction canceled: %s", strings.Join(reasons, "; "))
+}
+
+// Unwrap returns the error returned by DynamoDB.
+func (e *TransactionCanceledError) Unwrap() error {
+	return e.err
+}
+
+// Is reports whether the target is ErrTransactionCanceled or matches
+// the code of any of the reasons.
+func (e *TransactionCanceledError) Is(target error) bool {
+	if target == ErrTransactionCanceled {
+		return true
+	}
+	for _, r := range e.Reasons {
+		if reasonTarget(r.Code) == target {
+			return true
+		}
+		if target == ErrVersionConflict && r.Code == "ConditionalCheckFailed" {
+			if v, ok := r.Item.(versioner); ok && v.versioned() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func reasonTarget(code string) error {
+	switch code {
+	case "ConditionalCheckFailed":
+		return ErrConditionalCheckFailed
+	case "TransactionConflict":
+		return ErrTransactionConflict
+	case "ThrottlingError", "ProvisionedThroughputExceeded":
+		return ErrThrottled
+	}
+	return nil
+}
+
+// transactionCanceledError maps the cancellation reasons of a
+// canceled transaction to the items that caused them.
+func transactionCanceledError(err error, items []TransactionWriteItemer) error {
+	var tce *dynamodb.TransactionCanceledException
+	if !errors.As(err, &tce) {
+		return mapError(err)
+	}
+	e := &TransactionCanceledError{err: err}
+	for i, r := range tce.CancellationReasons {
+		if r == nil || r.Code == nil || *r.Code == "None" {
+			continue
+		}
+		reason := &TransactionCancellationReason{Index: i, Code: *r.Code}
+		if i < len(items) {
+			reason.Item = items[i]
+		}
+		if r.Message != nil {
+			reason.Message = *r.Message
+		}
+		e.Reasons = append(e.Reasons, reason)
+	}
+	return e
+}
@@ -0,0 +1,124 @@
+package dynago_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/twharmon/dynago"
+)
+
+func TestErrorsConditionalCheckFailed(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+	}
+	tableName := "bar"
+	p := Person{Name: "foo"}
+	awsErr := awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "The conditional request failed", nil)
+	ddb.MockPutError(&dynamodb.PutItemInput{
+		Item: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Person#foo")},
+		},
+		TableName: &tableName,
+	}, awsErr)
+	err := client.PutItem(&p).TableName(tableName).Exec()
+	if !errors.Is(err, dynago.ErrConditionalCheckFailed) {
+		t.Fatalf("want ErrConditionalCheckFailed; got %v", err)
+	}
+	if errors.Is(err, dynago.ErrThrottled) {
+		t.Fatalf("unexpected ErrThrottled")
+	}
+	var aerr awserr.Error
+	if !errors.As(err, &aerr) {
+		t.Fatalf("want awserr.Error; got %v", err)
+	}
+	assertEq(t, dynamodb.ErrCodeConditionalCheckFailedException, aerr.Code())
+	ddb.done()
+}
+
+func TestErrorsThrottled(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+	}
+	tableName := "bar"
+	p := Person{Name: "foo"}
+	ddb.MockPutError(&dynamodb.PutItemInput{
+		Item: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Person#foo")},
+		},
+		TableName: &tableName,
+	}, awserr.New(dynamodb.ErrCodeProvisionedThroughputExceededException, "", nil))
+	if err := client.PutItem(&p).TableName(tableName).Exec(); !errors.Is(err, dynago.ErrThrottled) {
+		t.Fatalf("want ErrThrottled; got %v", err)
+	}
+	ddb.done()
+}
+
+func TestErrorsTransactionCanceled(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+	}
+	tableName := "bar"
+	foo := Person{Name: "foo"}
+	bar := Person{Name: "bar"}
+	put := client.PutItem(&foo).TableName(tableName)
+	del := client.DeleteItem(&bar).TableName(tableName)
+	ddb.MockTransactWriteItemsError(&dynamodb.TransactWriteItemsInput{
+		TransactItems: []*dynamodb.TransactWriteItem{
+			{
+				Put: &dynamodb.Put{
+					Item: map[string]*dynamodb.AttributeValue{
+						"PK": {S: aws.String("Person#foo")},
+					},
+					TableName: &tableName,
+				},
+			},
+			{
+				Delete: &dynamodb.Delete{
+					Key: map[string]*dynamodb.AttributeValue{
+						"PK": {S: aws.String("Person#bar")},
+					},
+					TableName: &tableName,
+				},
+			},
+		},
+	}, &dynamodb.TransactionCanceledException{
+		Message_: aws.String("Transaction cancelled"),
+		CancellationReasons: []*dynamodb.CancellationReason{
+			{Code: aws.String("None")},
+			{Code: aws.String("ConditionalCheckFailed"), Message: aws.String("The conditional request failed")},
+		},
+	})
+	err := client.TransactionWriteItems().Items(put, del).Exec()
+	var tcErr *dynago.TransactionCanceledError
+	if !errors.As(err, &tcErr) {
+		t.Fatalf("want *TransactionCanceledError; got %v", err)
+	}
+	assertEq(t, []*dynago.TransactionCancellationReason{{
+		Index:   1,
+		Item:    del,
+		Code:    "ConditionalCheckFailed",
+		Message: "The conditional request failed",
+	}}, tcErr.Reasons)
+	if !errors.Is(err, dynago.ErrTransactionCanceled) {
+		t.Fatalf("want ErrTransactionCanceled")
+	}
+	if !errors.Is(err, dynago.ErrConditionalCheckFailed) {
+		t.Fatalf("want ErrConditionalCheckFailed")
+	}
+	if errors.Is(err, dynago.ErrTransactionConflict) {
+		t.Fatalf("unexpected ErrTransactionConflict")
+	}
+	ddb.done()
+}
@@ -0,0 +1,280 @@
+package dynago
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/twharmon/dynago/internal/tags"
+)
+
+// expression generates placeholders for the attribute names and
+// values of an expression. Placeholders are prefixed so that
+// expressions of different kinds can share the same
+// ExpressionAttributeNames and ExpressionAttributeValues.
+type expression struct {
+	prefix string
+	names  *map[string]*string
+	values *map[string]*dynamodb.AttributeValue
+	byName map[string]string
+	nameN  int
+	valueN int
+	dynago *Dynago
+	ty     reflect.Type
+}
+
+// expression returns an expression that adds placeholders to the
+// given names and values. The maps are created when the first
+// placeholder is added to them, since DynamoDB rejects empty maps.
+func (d *Dynago) expression(prefix string, ty reflect.Type, names *map[string]*string, values *map[string]*dynamodb.AttributeValue) *expression {
+	return &expression{
+		prefix: prefix,
+		names:  names,
+		values: values,
+		byName: make(map[string]string),
+		dynago: d,
+		ty:     ty,
+	}
+}
+
+// name returns the placeholder of the given attribute name.
+func (e *expression) name(name string) string {
+	if ph, ok := e.byName[name]; ok {
+		return ph
+	}
+	if *e.names == nil {
+		*e.names = make(map[string]*string)
+	}
+	ph := e.placeholder("#", &e.nameN, func(ph string) bool { return (*e.names)[ph] != nil })
+	(*e.names)[ph] = &name
+	e.byName[name] = ph
+	return ph
+}
+
+// path returns the placeholders of the given document path, such as
+// "Address.City" or "Tags[0]". Struct field names in the path are
+// resolved to attribute names.
+func (e *expression) path(path string) (string, error) {
+	path, err := e.dynago.attrPath(e.ty, path)
+	if err != nil {
+		return "", err
+	}
+	parts := strings.Split(path, ".")
+	for i, part := range parts {
+		idx := strings.IndexByte(part, '[')
+		if idx < 0 {
+			idx = len(part)
+		}
+		parts[i] = e.name(part[:idx]) + part[idx:]
+	}
+	return strings.Join(parts, "."), nil
+}
+
+// rawValue returns the placeholder of the given attribute value.
+func (e *expression) rawValue(av *dynamodb.AttributeValue) string {
+	if *e.values == nil {
+		*e.values = make(map[string]*dynamodb.AttributeValue)
+	}
+	ph := e.placeholder(":", &e.valueN, func(ph string) bool { return (*e.values)[ph] != nil })
+	(*e.values)[ph] = av
+	return ph
+}
+
+// value returns the placeholder of the given value, marshalled as
+// the value of the given attribute. If prefix is true, the value is
+// the prefix of the attribute's value.
+func (e *expression) value(attrName string, v interface{}, prefix bool) (string, error) {
+	av, err := e.dynago.attrValue(e.ty, attrName, v, prefix)
+	if err != nil {
+		return "", err
+	}
+	return e.rawValue(av), nil
+}
+
+// placeholder returns the next placeholder that is not taken.
+func (e *expression) placeholder(sym string, n *int, taken func(string) bool) string {
+	for {
+		ph := sym + e.prefix + strconv.Itoa(*n)
+		*n++
+		if !taken(ph) {
+			return ph
+		}
+	}
+}
+
+// itemType returns the struct type of an item, or of the items of a
+// Query or Scan operation.
+func itemType(items interface{}) reflect.Type {
+	ty := reflect.TypeOf(items)
+	for ty != nil && ty.Kind() == reflect.Pointer {
+		ty = ty.Elem()
+	}
+	if ty == nil || ty.Kind() != reflect.Slice {
+		return ty
+	}
+	ty = ty.Elem()
+	for ty.Kind() == reflect.Pointer {
+		ty = ty.Elem()
+	}
+	return ty
+}
+
+// attrValue marshals a value as the value of the attribute with the
+// given name of the struct type ty. The attribute's fmt tag is applied
+// to the value. If v is a struct of type ty, the attribute's value is
+// taken from it. If prefix is true, the fmt template is cut after the
+// placeholder the value is substituted in, so that the result can be
+// used with begins_with.
+func (d *Dynago) attrValue(ty reflect.Type, attrName string, v interface{}, prefix bool) (*dynamodb.AttributeValue, error) {
+	val := reflect.ValueOf(v)
+	f, err := d.attrField(ty, attrName)
+	if err != nil {
+		return nil, err
+	}
+	if f == nil {
+		return d.simpleMarshal(val, time.RFC3339)
+	}
+	for val.Kind() == reflect.Pointer {
+		val = val.Elem()
+	}
+	if val.IsValid() && val.Type() == ty {
+		return f.attrVal(val)
+	}
+	if tags.IsSetType(f.attrType) && val.IsValid() && (val.Kind() == reflect.Slice || val.Kind() == reflect.Array || isSetMap(val.Type())) {
+		av, err := d.marshalSet(val, f.attrType, f.layout)
+		if err == nil && av == nil {
+			err = fmt.Errorf("dynago: sets must not be empty")
+		}
+		return av, err
+	}
+	if f.attrType != "S" || f.fmt == "{}" {
+		layout := f.layout
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		return d.simpleMarshal(val, layout)
+	}
+	s, err := f.formatPartial(ty, val, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return &dynamodb.AttributeValue{S: &s}, nil
+}
+
+// attrPath resolves the struct field names of a document path of the
+// struct type ty to attribute names. Segments that are attribute
+// names already, or that can not be resolved, are left unchanged.
+func (d *Dynago) attrPath(ty reflect.Type, path string) (string, error) {
+	parts := strings.Split(path, ".")
+	for i, part := range parts {
+		idx := strings.IndexByte(part, '[')
+		if idx < 0 {
+			idx = len(part)
+		}
+		name := part[:idx]
+		f, err := d.attrField(ty, name)
+		if err != nil {
+			return "", err
+		}
+		if f == nil {
+			f, err = d.goField(ty, name)
+			if err != nil {
+				return "", err
+			}
+		}
+		if f == nil {
+			ty = nil
+			continue
+		}
+		if f.attrName != "-" {
+			parts[i] = f.attrName + part[idx:]
+		}
+		ty = ty.FieldByIndex(f.index).Type
+		for ty.Kind() == reflect.Pointer {
+			ty = ty.Elem()
+		}
+		for n := strings.Count(part[idx:], "["); n > 0 && (ty.Kind() == reflect.Slice || ty.Kind() == reflect.Array); n-- {
+			ty = ty.Elem()
+			for ty.Kind() == reflect.Pointer {
+				ty = ty.Elem()
+			}
+		}
+	}
+	return strings.Join(parts, "."), nil
+}
+
+// goField returns the field of the struct type ty with the given Go
+// name, or nil if there is none.
+func (d *Dynago) goField(ty reflect.Type, name string) (*field, error) {
+	if ty == nil || ty.Kind() != reflect.Struct {
+		return nil, nil
+	}
+	sf, ok := ty.FieldByName(name)
+	if !ok {
+		return nil, nil
+	}
+	c, err := d.codec(ty)
+	if err != nil {
+		return nil, fmt.Errorf("d.codec: %w", err)
+	}
+	for _, f := range c.fields {
+		if equalIndex(f.index, sf.Index) {
+			return f, nil
+		}
+	}
+	return nil, nil
+}
+
+// attrField returns the field of the struct type ty with the given
+// attribute name, or nil if there is none.
+func (d *Dynago) attrField(ty reflect.Type, attrName string) (*field, error) {
+	if attrName == "" || ty == nil || ty.Kind() != reflect.Struct {
+		return nil, nil
+	}
+	c, err := d.codec(ty)
+	if err != nil {
+		return nil, fmt.Errorf("d.codec: %w", err)
+	}
+	return c.byAttr[attrName], nil
+}
+
+// formatPartial formats a single value with the fmt template of the
+// field. The value is substituted in the {} placeholder, or in the
+// only placeholder of the template, which must be its first.
+func (f *field) formatPartial(ty reflect.Type, val reflect.Value, prefix bool) (string, error) {
+	var placeholders []int
+	target := -1
+	for i, p := range f.tmpl.Parts {
+		if p.Placeholder {
+			placeholders = append(placeholders, i)
+			if p.Name == "" {
+				target = i
+			}
+		}
+	}
+	if len(placeholders) == 1 {
+		target = placeholders[0]
+	}
+	if len(placeholders) == 0 || target != placeholders[0] || (len(placeholders) > 1 && !prefix) {
+		return "", fmt.Errorf("dynago: value of attribute %s must be a %s to format %q", f.attrName, ty, f.fmt)
+	}
+	var b strings.Builder
+	for _, p := range f.tmpl.Parts[:target] {
+		b.WriteString(p.Literal)
+	}
+	s, err := formatValue(val, f.tmpl.fields[target].layout)
+	if err != nil {
+		return "", fmt.Errorf("dynago: attribute %s: %w", f.attrName, err)
+	}
+	b.WriteString(f.tmpl.Value(target, s))
+	if target+1 < len(f.tmpl.Parts) {
+		b.WriteString(f.tmpl.Parts[target+1].Literal)
+		if target+2 < len(f.tmpl.Parts) && !prefix {
+			return "", fmt.Errorf("dynago: value of attribute %s must be a %s to format %q", f.attrName, ty, f.fmt)
+		}
+	}
+	return b.String(), nil
+}
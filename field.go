@@ -3,279 +3,335 @@ package dynago
 import (
 	"fmt"
 	"reflect"
-	"regexp"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/twharmon/dynago/internal/tags"
 )
 
 var timeType = reflect.TypeOf(time.Now())
-var fmtRegExp = regexp.MustCompile(`\{([A-Z]?[a-zA-Z0-9_]*)\}`)
 
 type field struct {
 	attrName    string
 	attrType    string
 	fmt         string
-	fmtRegExps  map[string]*regexp.Regexp
+	tmpl        *template
 	layout      string
-	index       int
+	name        string
+	index       []int
 	attrsToCopy []string
+	version     bool
+	auto        string
+	ttl         bool
+	omitEmpty   bool
+	null        bool
+	custom      bool
+	typ         reflect.Type
+	encode      encoder
+	decode      decoder
 	client      *Dynago
 }
 
-func (d *Dynago) field(sf reflect.StructField, index int) (*field, error) {
-	var f field
-	f.index = index
-	f.client = d
+// field returns the field of the struct field sf with the index
+// sequence, and its tags as parsed by the tags package.
+func (d *Dynago) field(sf reflect.StructField, index []int) (*field, *tags.Field, error) {
+	ty := derefType(sf.Type)
+	tf, err := tags.Parse(sf.Name, index, sf.IsExported(), sf.Tag, d.tagNames(), tagType(ty), d.config.DefaultOmitEmpty, d.config.NullPointers)
+	if err != nil {
+		return nil, nil, fmt.Errorf("dynago: %w", err)
+	}
+	f := field{
+		attrName:    tf.AttrName,
+		name:        tf.Name,
+		index:       tf.Index,
+		client:      d,
+		attrType:    tf.AttrType,
+		fmt:         tf.Fmt,
+		tmpl:        &template{Template: tf.Template},
+		layout:      tf.Layout,
+		attrsToCopy: tf.AttrsToCopy,
+		version:     tf.Version,
+		auto:        tf.Auto,
+		ttl:         tf.TTL,
+		omitEmpty:   tf.OmitEmpty,
+		null:        tf.Null,
+	}
 	if sf.IsExported() {
-		if tag, ok := sf.Tag.Lookup(d.config.AttrTagName); ok {
-			f.attrName = tag
-		} else {
-			f.attrName = sf.Name
-		}
-	} else {
-		f.attrName = "-"
-		return &f, nil
+		f.typ = ty
+		f.custom = isCustom(ty)
 	}
-	ty := sf.Type
-	for ty.Kind() == reflect.Pointer {
-		ty = ty.Elem()
+	return &f, tf, nil
+}
+
+// tagNames returns the names of the tags in the config.
+func (d *Dynago) tagNames() tags.Names {
+	return tags.Names{
+		Attr:    d.config.AttrTagName,
+		Fmt:     d.config.FmtTagName,
+		Type:    d.config.TypeTagName,
+		Layout:  d.config.LayoutTagName,
+		Copy:    d.config.AttrsToCopyTagName,
+		Version: d.config.VersionTagName,
+		Auto:    d.config.AutoTagName,
+		TTL:     d.config.TTLTagName,
 	}
-	kind := ty.Kind()
-	if tag, ok := sf.Tag.Lookup(d.config.TypeTagName); ok {
-		f.attrType = tag
-	} else {
-		switch kind {
-		case reflect.String:
-			f.attrType = "S"
-		case reflect.Int, reflect.Uint, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float64, reflect.Float32:
-			f.attrType = "N"
-		case reflect.Bool:
-			f.attrType = "BOOL"
-		case reflect.Slice:
-			switch ty.Elem().Kind() {
-			case reflect.Uint8:
-				f.attrType = "B"
-			default:
-				f.attrType = "L"
-			}
-		case reflect.Struct:
-			switch ty {
-			case timeType:
-				f.attrType = "S"
-			default:
-				f.attrType = "M"
-			}
+}
+
+// tagType describes the dereferenced type ty of a field to the tags
+// package.
+func tagType(ty reflect.Type) tags.Type {
+	t := tags.Type{String: ty.String()}
+	switch ty.Kind() {
+	case reflect.String:
+		t.Kind = tags.KindString
+	case reflect.Bool:
+		t.Kind = tags.KindBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		t.Kind = tags.KindInt
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		t.Kind = tags.KindUint
+	case reflect.Float32, reflect.Float64:
+		t.Kind = tags.KindFloat
+	case reflect.Slice:
+		t.Kind = tags.KindSlice
+		if ty.Elem().Kind() == reflect.Uint8 {
+			t.Kind = tags.KindBytes
+		}
+		t.TimeElem = derefType(ty.Elem()) == timeType
+	case reflect.Array:
+		t.Kind = tags.KindArray
+	case reflect.Map:
+		t.Kind = tags.KindMap
+		t.SetMap = isSetMap(ty)
+	case reflect.Struct:
+		t.Kind = tags.KindStruct
+		if ty == timeType {
+			t.Kind = tags.KindTime
 		}
+	case reflect.Interface:
+		t.Kind = tags.KindInterface
 	}
-	if tag, ok := sf.Tag.Lookup(d.config.FmtTagName); ok {
-		f.fmt = tag
-		f.attrType = "S"
-	} else {
-		f.fmt = "{}"
+	if ty != timeType {
+		t.Text = (ty.Implements(textMarshalerType) || reflect.PointerTo(ty).Implements(textMarshalerType)) && reflect.PointerTo(ty).Implements(textUnmarshalerType)
 	}
-	f.fmtRegExps = make(map[string]*regexp.Regexp)
-	for _, match := range fmtRegExp.FindAllString(f.fmt, -1) {
-		fname := trimDelims(match)
-		f.fmtRegExps[fname] = regexp.MustCompile("(?s)^" + fmtRegExp.ReplaceAllString(strings.ReplaceAll(f.fmt, match, "(.*?)"), ".*?") + "$")
+	return t
+}
+
+// fields returns the fields of the struct type ty, including the
+// fields of embedded structs without an attribute name, which are
+// promoted as described by tags.Visible.
+func (d *Dynago) fields(ty reflect.Type) ([]*field, error) {
+	type embedded struct {
+		ty    reflect.Type
+		index []int
 	}
-	if tag, ok := sf.Tag.Lookup(d.config.LayoutTagName); ok {
-		f.layout = tag
-	} else {
-		switch kind {
-		case reflect.Struct:
-			switch ty {
-			case timeType:
-				f.layout = time.RFC3339
+	var fields []*field
+	var parsed []*tags.Field
+	visited := make(map[reflect.Type]bool)
+	next := []embedded{{ty: ty}}
+	for len(next) > 0 {
+		current := next
+		next = nil
+		for _, e := range current {
+			if visited[e.ty] {
+				continue
 			}
-		case reflect.Slice:
-			elTy := ty.Elem()
-			for elTy.Kind() == reflect.Pointer {
-				elTy = elTy.Elem()
+			visited[e.ty] = true
+			for i := 0; i < e.ty.NumField(); i++ {
+				sf := e.ty.Field(i)
+				index := append(append([]int(nil), e.index...), i)
+				name, _, _ := strings.Cut(sf.Tag.Get(d.config.AttrTagName), ",")
+				if sf.Anonymous {
+					ft := derefType(sf.Type)
+					if !sf.IsExported() && sf.Type.Kind() == reflect.Pointer {
+						// Nil pointers to unexported structs can
+						// not be allocated.
+						continue
+					}
+					if name == "" && ft.Kind() == reflect.Struct && ft != timeType && !isCustom(ft) {
+						next = append(next, embedded{ty: ft, index: index})
+						continue
+					}
+				}
+				f, tf, err := d.field(sf, index)
+				if err != nil {
+					return nil, err
+				}
+				fields = append(fields, f)
+				parsed = append(parsed, tf)
 			}
-			switch elTy {
-			case timeType:
-				f.layout = time.RFC3339
+		}
+	}
+	typeOf := func(i int) tags.Type {
+		return tagType(fields[i].typ)
+	}
+	for i, f := range fields {
+		if f.attrName == "-" || f.attrType != "S" || (f.custom && f.fmt == "{}") {
+			continue
+		}
+		resolved, err := f.tmpl.Resolve(i, parsed, typeOf)
+		if err != nil {
+			return nil, fmt.Errorf("dynago: %w", err)
+		}
+		f.tmpl.fields = make([]*field, len(resolved))
+		for j, k := range resolved {
+			if k >= 0 {
+				f.tmpl.fields[j] = fields[k]
 			}
 		}
 	}
-	if tag, ok := sf.Tag.Lookup(d.config.AttrsToCopyTagName); ok {
-		f.attrsToCopy = strings.Split(tag, ",")
+	var visible []*field
+	for _, i := range tags.Visible(parsed) {
+		visible = append(visible, fields[i])
 	}
-	return &f, nil
+	return visible, nil
 }
 
-func (f *field) format(v reflect.Value) (*string, error) {
-	output := f.fmt
-	for _, match := range fmtRegExp.FindAllString(f.fmt, -1) {
-		var fval reflect.Value
-		var refFieldLayout string
-		if match == "{}" {
-			fval = v.Field(f.index)
-			refFieldLayout = f.layout
-		} else {
-			cache, err := f.client.cachedStruct(v.Type()) // TODO: do this lazily
-			if err != nil {
-				return nil, err
-			}
-			fname := trimDelims(match)
-			vt := v.Type()
-			for i := 0; i < vt.NumField(); i++ {
-				fld := vt.Field(i)
-				if fld.Name == fname {
-					fval = v.Field(i)
-					ff := cache[i]
-					refFieldLayout = ff.layout
-					break
+// value returns the field of the struct value v. Nil pointers to
+// embedded structs the field is promoted from are allocated if alloc is
+// true and they can be set. Otherwise, the zero Value is returned.
+func (f *field) value(v reflect.Value, alloc bool) reflect.Value {
+	for i, x := range f.index {
+		if i > 0 {
+			for v.Kind() == reflect.Pointer {
+				if v.IsNil() {
+					if !alloc || !v.CanSet() {
+						return reflect.Value{}
+					}
+					v.Set(reflect.New(v.Type().Elem()))
 				}
+				v = v.Elem()
 			}
 		}
-		for fval.Kind() == reflect.Pointer {
-			fval = fval.Elem()
-		}
-		switch fval.Kind() {
-		case reflect.String:
-			output = strings.ReplaceAll(output, match, fval.String())
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			output = strings.ReplaceAll(output, match, strconv.FormatInt(fval.Int(), 10))
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			output = strings.ReplaceAll(output, match, strconv.FormatUint(fval.Uint(), 10))
-		case reflect.Float32:
-			output = strings.ReplaceAll(output, match, strconv.FormatFloat(fval.Float(), 'f', -1, 32))
-		case reflect.Float64:
-			output = strings.ReplaceAll(output, match, strconv.FormatFloat(fval.Float(), 'f', -1, 64))
-		case reflect.Struct:
-			switch val := fval.Interface().(type) {
-			case time.Time:
-				output = strings.ReplaceAll(output, match, val.Format(refFieldLayout))
-			}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// equalIndex reports whether the index sequences a and b are equal.
+func equalIndex(a []int, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
 		}
 	}
-	return &output, nil
+	return true
 }
 
+// derefType returns the type ty points to, following any number of
+// pointers.
+func derefType(ty reflect.Type) reflect.Type {
+	for ty.Kind() == reflect.Pointer {
+		ty = ty.Elem()
+	}
+	return ty
+}
+
+func (f *field) format(v reflect.Value) (*string, error) {
+	s, err := f.tmpl.format(v)
+	if err != nil {
+		return nil, fmt.Errorf("dynago: attribute %s: %w", f.attrName, err)
+	}
+	return &s, nil
+}
+
+// parse parses the attribute value s with the fmt template of the
+// field, setting the fields referenced in it. Nothing is set if s does
+// not match the template, so that items of other types in the same
+// table can be unmarshalled.
 func (f *field) parse(s string, v reflect.Value) error {
-	for _, match := range fmtRegExp.FindAllString(f.fmt, -1) {
-		fname := trimDelims(match)
+	_, err := f.tmpl.parse(s, v)
+	return err
+}
 
-		strSubs := f.fmtRegExps[fname].FindStringSubmatch(s)
-		if strSubs == nil {
-			continue
-		}
-		str := strSubs[1]
-		var fval reflect.Value
-		var refFieldLayout string
-		if fname == "" {
-			fval = v.Field(f.index)
-			refFieldLayout = f.layout
-		} else {
-			fval = v.FieldByName(fname)
-			cache, err := f.client.cachedStruct(v.Type()) // TODO: do this lazily
-			if err != nil {
-				return err
-			}
-			vt := v.Type()
-			for i := 0; i < vt.NumField(); i++ {
-				fld := vt.Field(i)
-				if fld.Name == fname {
-					fval = v.Field(i)
-					ff := cache[i]
-					refFieldLayout = ff.layout
-					break
-				}
+// omit reports whether the field of v is left out of the item, and
+// returns the NULL attribute value to write in its place instead if
+// it is a nil pointer that is written as NULL.
+func (f *field) omit(v reflect.Value) (bool, *dynamodb.AttributeValue) {
+	fv := f.value(v, false)
+	if !fv.IsValid() {
+		return true, nil
+	}
+	if f.omitEmpty && isEmpty(fv) {
+		return true, nil
+	}
+	for fv.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			if f.null {
+				null := true
+				return false, &dynamodb.AttributeValue{NULL: &null}
 			}
+			return true, nil
 		}
-		for fval.Kind() == reflect.Pointer {
-			fval = fval.Elem()
-		}
-		fty := fval.Type()
-		switch fty.Kind() {
-		case reflect.String:
-			fval.SetString(str)
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			val, err := strconv.ParseInt(str, 10, fval.Type().Bits())
-			if err != nil {
-				return err
-			}
-			fval.SetInt(val)
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			val, err := strconv.ParseUint(str, 10, fval.Type().Bits())
-			if err != nil {
-				return err
-			}
-			fval.SetUint(val)
-		case reflect.Float32, reflect.Float64:
-			val, err := strconv.ParseFloat(str, fval.Type().Bits())
-			if err != nil {
-				return err
-			}
-			fval.SetFloat(val)
-		case reflect.Struct:
-			switch fty {
-			case timeType:
-				t, err := time.Parse(refFieldLayout, str)
-				if err != nil {
-					return fmt.Errorf("time.Parse: %w", err)
-				}
-				fval.Set(reflect.ValueOf(t))
-			}
+		fv = fv.Elem()
+	}
+	return false, nil
+}
+
+// isEmpty reports whether v is false, 0, a nil pointer or interface,
+// an empty string, slice or map, or a zero time.Time. Pointers to
+// empty values are not empty.
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return v.Interface().(time.Time).IsZero()
 		}
 	}
-	return nil
+	return false
 }
 
 func (f *field) attrVal(v reflect.Value) (*dynamodb.AttributeValue, error) {
-	fv := v.Field(f.index)
+	fv := f.value(v, false)
+	if !fv.IsValid() {
+		return nil, nil
+	}
 	for fv.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			return nil, nil
+		}
 		fv = fv.Elem()
 	}
-	switch f.attrType {
-	case "S":
-		s, err := f.format(v)
-		if err != nil {
-			return nil, err
-		}
-		return &dynamodb.AttributeValue{S: s}, nil
-	case "SS":
-		av := &dynamodb.AttributeValue{}
-		ss := fv.Interface().([]string)
-		av.SS = make([]*string, len(ss))
-		for i := range ss {
-			av.SS[i] = &ss[i]
-		}
-		return av, nil
+	if f.ttl && fv.Interface().(time.Time).IsZero() {
+		return nil, nil
 	}
-	return f.client.simpleMarshal(fv, f.layout)
+	return f.encode(v, fv)
 }
 
 func (f *field) unmarshal(item map[string]*dynamodb.AttributeValue, v reflect.Value) error {
-	fv := v.Field(f.index)
+	av := item[f.attrName]
+	if av == nil {
+		return nil
+	}
+	fv := f.value(v, true)
+	if !fv.IsValid() {
+		return nil
+	}
+	if av.NULL != nil && *av.NULL {
+		fv.Set(reflect.Zero(fv.Type()))
+		return nil
+	}
 	for fv.Kind() == reflect.Pointer {
 		if fv.IsNil() {
 			fv.Set(reflect.New(fv.Type().Elem()))
 		}
 		fv = fv.Elem()
 	}
-	switch f.attrType {
-	case "S":
-		if item[f.attrName] != nil && item[f.attrName].S != nil {
-			if err := f.parse(*item[f.attrName].S, v); err != nil {
-				return fmt.Errorf("parse: %s", err)
-			}
-		}
-	case "SS":
-		if item[f.attrName] != nil && item[f.attrName].SS != nil {
-			ssptr := item[f.attrName].SS
-			ss := make([]string, len(ssptr))
-			for i := range ssptr {
-				ss[i] = *ssptr[i]
-			}
-			fv.Set(reflect.ValueOf(ss))
-		}
-	default:
-		return f.client.simpleUnmarshal(fv, item[f.attrName], f.layout)
-	}
-	return nil
+	return f.decode(v, fv, av)
 }
@@ -1,6 +1,7 @@
 package dynago
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -12,9 +13,16 @@ var ErrItemNotFound = errors.New("item not found")
 
 // GetItem represents a GetItem operation.
 type GetItem struct {
-	item   Keyer
-	input  *dynamodb.GetItemInput
-	dynago *Dynago
+	item          Keyer
+	input         *dynamodb.GetItemInput
+	output        *GetItemOutput
+	filterExpired bool
+	dynago        *Dynago
+}
+
+// GetItemOutput represents the output of a GetItem operation.
+type GetItemOutput struct {
+	ConsumedCapacity *dynamodb.ConsumedCapacity
 }
 
 // GetItem returns a GetItem operation.
@@ -56,19 +64,73 @@ func (q *GetItem) ConsistentRead(consisten bool) *GetItem {
 	return q
 }
 
+// FilterExpired sets the operation to return ErrItemNotFound if the
+// TTL attribute of the item has passed, since DynamoDB may take up to
+// a few days to delete expired items.
+func (q *GetItem) FilterExpired() *GetItem {
+	q.filterExpired = true
+	return q
+}
+
+// ReturnConsumedCapacity sets ReturnConsumedCapacity.
+func (q *GetItem) ReturnConsumedCapacity(val string) *GetItem {
+	q.input.ReturnConsumedCapacity = &val
+	return q
+}
+
+// Output sets the output to be populated when the operation is
+// executed.
+func (q *GetItem) Output(output *GetItemOutput) *GetItem {
+	q.output = output
+	return q
+}
+
 // Exec exeutes the operation.
 func (q *GetItem) Exec() error {
+	return q.ExecWithContext(context.Background())
+}
+
+// ExecWithContext executes the operation with the given context.
+func (q *GetItem) ExecWithContext(ctx context.Context) error {
 	var err error
 	q.input.Key, err = q.dynago.key(q.item)
 	if err != nil {
 		return fmt.Errorf("q.dynago.key: %w", err)
 	}
-	output, err := q.dynago.ddb.GetItem(q.input)
+	output, err := q.dynago.ddb.GetItemWithContext(ctx, q.input)
 	if err != nil {
-		return fmt.Errorf("d.ddb.GetItem: %w", err)
+		return fmt.Errorf("d.ddb.GetItem: %w", mapError(err))
+	}
+	if q.output != nil {
+		q.output.ConsumedCapacity = output.ConsumedCapacity
 	}
 	if len(output.Item) == 0 {
 		return ErrItemNotFound
 	}
+	if q.filterExpired {
+		expired, err := q.dynago.expired(itemType(q.item), output.Item)
+		if err != nil {
+			return err
+		}
+		if expired {
+			return ErrItemNotFound
+		}
+	}
 	return q.dynago.Unmarshal(output.Item, q.item)
 }
+
+// TransactionGetItem implements the TransactionGetItemer interface.
+func (q *GetItem) TransactionGetItem() (*dynamodb.TransactGetItem, Keyer, error) {
+	key, err := q.dynago.key(q.item)
+	if err != nil {
+		return nil, nil, fmt.Errorf("q.dynago.key: %w", err)
+	}
+	return &dynamodb.TransactGetItem{
+		Get: &dynamodb.Get{
+			Key:                      key,
+			TableName:                q.input.TableName,
+			ProjectionExpression:     q.input.ProjectionExpression,
+			ExpressionAttributeNames: q.input.ExpressionAttributeNames,
+		},
+	}, q.item, nil
+}
@@ -1,6 +1,7 @@
 package dynago_test
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"testing"
@@ -339,3 +340,67 @@ func TestGetItemDuration(t *testing.T) {
 	assertEq(t, want, got)
 	ddb.done()
 }
+
+func TestGetItemReturnConsumedCapacity(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		*CompositeTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+	}
+	p := Person{Name: "foo"}
+	tableName := "bar"
+	cc := &dynamodb.ConsumedCapacity{TableName: &tableName, CapacityUnits: aws.Float64(0.5)}
+	ddb.MockGet(&dynamodb.GetItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Person#foo")},
+		},
+		TableName:              &tableName,
+		ConsistentRead:         aws.Bool(false),
+		ReturnConsumedCapacity: aws.String(dynamodb.ReturnConsumedCapacityTotal),
+	}, &dynamodb.GetItemOutput{
+		Item: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Person#foo")},
+		},
+		ConsumedCapacity: cc,
+	})
+	var output dynago.GetItemOutput
+	if err := client.GetItem(&p).
+		TableName(tableName).
+		ReturnConsumedCapacity(dynamodb.ReturnConsumedCapacityTotal).
+		Output(&output).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, cc, output.ConsumedCapacity)
+	ddb.done()
+}
+
+func TestGetItemExecWithContext(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+	}
+	tableName := "bar"
+	ddb.MockGet(&dynamodb.GetItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Person#foo")},
+		},
+		TableName:      &tableName,
+		ConsistentRead: aws.Bool(false),
+	}, &dynamodb.GetItemOutput{
+		Item: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Person#foo")},
+		},
+	})
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "foo")
+	got := Person{Name: "foo"}
+	if err := client.GetItem(&got).TableName(tableName).ExecWithContext(ctx); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, "foo", ddb.ctx.Value(ctxKey{}))
+	ddb.done()
+}
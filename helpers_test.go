@@ -2,8 +2,11 @@ package dynago_test
 
 import (
 	"reflect"
+	"sync"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 )
@@ -20,20 +23,45 @@ type Fatalfer interface {
 
 type ddbMock struct {
 	dynamodbiface.DynamoDBAPI
-	t               Fatalfer
-	getItemInput    *dynamodb.GetItemInput
-	getItemOutput   *dynamodb.GetItemOutput
-	queryInput      *dynamodb.QueryInput
-	queryOutput     *dynamodb.QueryOutput
-	scanInput       *dynamodb.ScanInput
-	scanOutput      *dynamodb.ScanOutput
-	putItemInput    *dynamodb.PutItemInput
-	deleteItemInput *dynamodb.DeleteItemInput
-	txWriteInput    *dynamodb.TransactWriteItemsInput
-	updateItemInput *dynamodb.UpdateItemInput
-}
-
-func (m *ddbMock) GetItem(i *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+	mtx              sync.Mutex
+	ctx              aws.Context
+	t                Fatalfer
+	getItemInput     *dynamodb.GetItemInput
+	getItemOutput    *dynamodb.GetItemOutput
+	queryInputs      []*dynamodb.QueryInput
+	queryOutputs     []*dynamodb.QueryOutput
+	scanCalls        []scanCall
+	putItemInput     *dynamodb.PutItemInput
+	putItemOutput    *dynamodb.PutItemOutput
+	putItemErr       error
+	deleteItemInput  *dynamodb.DeleteItemInput
+	deleteItemOutput *dynamodb.DeleteItemOutput
+	txWriteInput     *dynamodb.TransactWriteItemsInput
+	txWriteOutput    *dynamodb.TransactWriteItemsOutput
+	txWriteErr       error
+	updateItemInput  *dynamodb.UpdateItemInput
+	updateItemOutput *dynamodb.UpdateItemOutput
+	batchGetInputs   []*dynamodb.BatchGetItemInput
+	batchGetOutputs  []*dynamodb.BatchGetItemOutput
+	batchWriteCalls  []batchWriteCall
+	txGetInput       *dynamodb.TransactGetItemsInput
+	txGetOutput      *dynamodb.TransactGetItemsOutput
+}
+
+type scanCall struct {
+	input  *dynamodb.ScanInput
+	output *dynamodb.ScanOutput
+	err    error
+}
+
+type batchWriteCall struct {
+	input  *dynamodb.BatchWriteItemInput
+	output *dynamodb.BatchWriteItemOutput
+	err    error
+}
+
+func (m *ddbMock) GetItemWithContext(ctx aws.Context, i *dynamodb.GetItemInput, opts ...request.Option) (*dynamodb.GetItemOutput, error) {
+	m.ctx = ctx
 	if !reflect.DeepEqual(i, m.getItemInput) {
 		m.t.Fatalf("want %v; got %v", m.getItemInput, i)
 	}
@@ -48,16 +76,45 @@ func (m *ddbMock) MockGet(i *dynamodb.GetItemInput, o *dynamodb.GetItemOutput) {
 	m.getItemOutput = o
 }
 
-func (m *ddbMock) MockTransactWriteItems(i *dynamodb.TransactWriteItemsInput) {
+func (m *ddbMock) MockTransactWriteItems(i *dynamodb.TransactWriteItemsInput, o ...*dynamodb.TransactWriteItemsOutput) {
 	m.txWriteInput = i
+	m.txWriteOutput = &dynamodb.TransactWriteItemsOutput{}
+	if len(o) > 0 {
+		m.txWriteOutput = o[0]
+	}
 }
 
-func (m *ddbMock) TransactWriteItems(i *dynamodb.TransactWriteItemsInput) (*dynamodb.TransactWriteItemsOutput, error) {
+func (m *ddbMock) TransactWriteItemsWithContext(ctx aws.Context, i *dynamodb.TransactWriteItemsInput, opts ...request.Option) (*dynamodb.TransactWriteItemsOutput, error) {
+	m.ctx = ctx
 	if !reflect.DeepEqual(i, m.txWriteInput) {
 		m.t.Fatalf("want %v; got %v", m.txWriteInput, i)
 	}
+	o, err := m.txWriteOutput, m.txWriteErr
 	m.txWriteInput = nil
-	return nil, nil
+	m.txWriteOutput = nil
+	m.txWriteErr = nil
+	return o, err
+}
+
+func (m *ddbMock) MockTransactWriteItemsError(i *dynamodb.TransactWriteItemsInput, err error) {
+	m.txWriteInput = i
+	m.txWriteErr = err
+}
+
+func (m *ddbMock) MockTransactGetItems(i *dynamodb.TransactGetItemsInput, o *dynamodb.TransactGetItemsOutput) {
+	m.txGetInput = i
+	m.txGetOutput = o
+}
+
+func (m *ddbMock) TransactGetItemsWithContext(ctx aws.Context, i *dynamodb.TransactGetItemsInput, opts ...request.Option) (*dynamodb.TransactGetItemsOutput, error) {
+	m.ctx = ctx
+	if !reflect.DeepEqual(i, m.txGetInput) {
+		m.t.Fatalf("want %v; got %v", m.txGetInput, i)
+	}
+	o := m.txGetOutput
+	m.txGetInput = nil
+	m.txGetOutput = nil
+	return o, nil
 }
 
 func (m *ddbMock) done() {
@@ -70,10 +127,10 @@ func (m *ddbMock) done() {
 	if m.deleteItemInput != nil {
 		m.t.Fatalf("expectations not met")
 	}
-	if m.queryInput != nil {
+	if len(m.queryInputs) > 0 {
 		m.t.Fatalf("expectations not met")
 	}
-	if m.scanInput != nil {
+	if len(m.scanCalls) > 0 {
 		m.t.Fatalf("expectations not met")
 	}
 	if m.getItemOutput != nil {
@@ -85,42 +142,78 @@ func (m *ddbMock) done() {
 	if m.updateItemInput != nil {
 		m.t.Fatalf("expectations not met")
 	}
+	if len(m.batchGetInputs) > 0 {
+		m.t.Fatalf("expectations not met")
+	}
+	if len(m.batchWriteCalls) > 0 {
+		m.t.Fatalf("expectations not met")
+	}
+	if m.txGetInput != nil {
+		m.t.Fatalf("expectations not met")
+	}
 }
 
-func (m *ddbMock) PutItem(i *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+func (m *ddbMock) PutItemWithContext(ctx aws.Context, i *dynamodb.PutItemInput, opts ...request.Option) (*dynamodb.PutItemOutput, error) {
+	m.ctx = ctx
 	if !reflect.DeepEqual(i, m.putItemInput) {
 		m.t.Fatalf("want %v; got %v", m.putItemInput, i)
 	}
+	o, err := m.putItemOutput, m.putItemErr
 	m.putItemInput = nil
-	return nil, nil
+	m.putItemOutput = nil
+	m.putItemErr = nil
+	return o, err
+}
+
+func (m *ddbMock) MockPutError(i *dynamodb.PutItemInput, err error) {
+	m.putItemInput = i
+	m.putItemErr = err
 }
 
-func (m *ddbMock) MockPut(i *dynamodb.PutItemInput) {
+func (m *ddbMock) MockPut(i *dynamodb.PutItemInput, o ...*dynamodb.PutItemOutput) {
 	m.putItemInput = i
+	m.putItemOutput = &dynamodb.PutItemOutput{}
+	if len(o) > 0 {
+		m.putItemOutput = o[0]
+	}
 }
 
-func (m *ddbMock) UpdateItem(i *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+func (m *ddbMock) UpdateItemWithContext(ctx aws.Context, i *dynamodb.UpdateItemInput, opts ...request.Option) (*dynamodb.UpdateItemOutput, error) {
+	m.ctx = ctx
 	if !reflect.DeepEqual(i, m.updateItemInput) {
 		m.t.Fatalf("want %v; got %v", m.updateItemInput, i)
 	}
+	o := m.updateItemOutput
 	m.updateItemInput = nil
-	return nil, nil
+	m.updateItemOutput = nil
+	return o, nil
 }
 
-func (m *ddbMock) MockUpdate(i *dynamodb.UpdateItemInput) {
+func (m *ddbMock) MockUpdate(i *dynamodb.UpdateItemInput, o ...*dynamodb.UpdateItemOutput) {
 	m.updateItemInput = i
+	m.updateItemOutput = &dynamodb.UpdateItemOutput{}
+	if len(o) > 0 {
+		m.updateItemOutput = o[0]
+	}
 }
 
-func (m *ddbMock) DeleteItem(i *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+func (m *ddbMock) DeleteItemWithContext(ctx aws.Context, i *dynamodb.DeleteItemInput, opts ...request.Option) (*dynamodb.DeleteItemOutput, error) {
+	m.ctx = ctx
 	if !reflect.DeepEqual(i, m.deleteItemInput) {
 		m.t.Fatalf("want %v; got %v", m.deleteItemInput, i)
 	}
+	o := m.deleteItemOutput
 	m.deleteItemInput = nil
-	return nil, nil
+	m.deleteItemOutput = nil
+	return o, nil
 }
 
-func (m *ddbMock) MockDelete(i *dynamodb.DeleteItemInput) {
+func (m *ddbMock) MockDelete(i *dynamodb.DeleteItemInput, o ...*dynamodb.DeleteItemOutput) {
 	m.deleteItemInput = i
+	m.deleteItemOutput = &dynamodb.DeleteItemOutput{}
+	if len(o) > 0 {
+		m.deleteItemOutput = o[0]
+	}
 }
 
 func mock(t Fatalfer) *ddbMock {
@@ -128,32 +221,96 @@ func mock(t Fatalfer) *ddbMock {
 	return &m
 }
 
-func (m *ddbMock) Query(i *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
-	if !reflect.DeepEqual(i, m.queryInput) {
-		m.t.Fatalf("want %v; got %v", m.queryInput, i)
+func (m *ddbMock) QueryWithContext(ctx aws.Context, i *dynamodb.QueryInput, opts ...request.Option) (*dynamodb.QueryOutput, error) {
+	m.ctx = ctx
+	if len(m.queryInputs) == 0 {
+		m.t.Fatalf("unexpected call with %v", i)
+	}
+	if !reflect.DeepEqual(i, m.queryInputs[0]) {
+		m.t.Fatalf("want %v; got %v", m.queryInputs[0], i)
 	}
-	o := *m.queryOutput
-	m.queryInput = nil
-	m.queryOutput = nil
+	o := *m.queryOutputs[0]
+	m.queryInputs = m.queryInputs[1:]
+	m.queryOutputs = m.queryOutputs[1:]
 	return &o, nil
 }
 
-func (m *ddbMock) Scan(i *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
-	if !reflect.DeepEqual(i, m.scanInput) {
-		m.t.Fatalf("want %v; got %v", m.scanInput, i)
+// ScanWithContext returns the output of the first expected call
+// matching the input, so calls of parallel scans can happen in any
+// order.
+func (m *ddbMock) ScanWithContext(ctx aws.Context, i *dynamodb.ScanInput, opts ...request.Option) (*dynamodb.ScanOutput, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.ctx = ctx
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
-	o := *m.scanOutput
-	m.scanInput = nil
-	m.scanOutput = nil
-	return &o, nil
+	if len(m.scanCalls) == 0 {
+		m.t.Fatalf("unexpected call with %v", i)
+	}
+	for j, c := range m.scanCalls {
+		if reflect.DeepEqual(i, c.input) {
+			m.scanCalls = append(m.scanCalls[:j:j], m.scanCalls[j+1:]...)
+			if c.err != nil {
+				return nil, c.err
+			}
+			o := *c.output
+			return &o, nil
+		}
+	}
+	m.t.Fatalf("want %v; got %v", m.scanCalls[0].input, i)
+	return nil, nil
 }
 
-func (m *ddbMock) MockScan(i *dynamodb.ScanInput, o *dynamodb.ScanOutput) {
-	m.scanInput = i
-	m.scanOutput = o
+func (m *ddbMock) MockScan(i *dynamodb.ScanInput, o *dynamodb.ScanOutput, err ...error) {
+	c := scanCall{input: i, output: o}
+	if len(err) > 0 {
+		c.err = err[0]
+	}
+	m.scanCalls = append(m.scanCalls, c)
 }
 
 func (m *ddbMock) MockQuery(i *dynamodb.QueryInput, o *dynamodb.QueryOutput) {
-	m.queryInput = i
-	m.queryOutput = o
+	m.queryInputs = append(m.queryInputs, i)
+	m.queryOutputs = append(m.queryOutputs, o)
+}
+
+func (m *ddbMock) BatchGetItemWithContext(ctx aws.Context, i *dynamodb.BatchGetItemInput, opts ...request.Option) (*dynamodb.BatchGetItemOutput, error) {
+	m.ctx = ctx
+	if len(m.batchGetInputs) == 0 {
+		m.t.Fatalf("unexpected call with %v", i)
+	}
+	if !reflect.DeepEqual(i, m.batchGetInputs[0]) {
+		m.t.Fatalf("want %v; got %v", m.batchGetInputs[0], i)
+	}
+	o := m.batchGetOutputs[0]
+	m.batchGetInputs = m.batchGetInputs[1:]
+	m.batchGetOutputs = m.batchGetOutputs[1:]
+	return o, nil
+}
+
+func (m *ddbMock) MockBatchGet(i *dynamodb.BatchGetItemInput, o *dynamodb.BatchGetItemOutput) {
+	m.batchGetInputs = append(m.batchGetInputs, i)
+	m.batchGetOutputs = append(m.batchGetOutputs, o)
+}
+
+func (m *ddbMock) BatchWriteItemWithContext(ctx aws.Context, i *dynamodb.BatchWriteItemInput, opts ...request.Option) (*dynamodb.BatchWriteItemOutput, error) {
+	m.ctx = ctx
+	if len(m.batchWriteCalls) == 0 {
+		m.t.Fatalf("unexpected call with %v", i)
+	}
+	c := m.batchWriteCalls[0]
+	if !reflect.DeepEqual(i, c.input) {
+		m.t.Fatalf("want %v; got %v", c.input, i)
+	}
+	m.batchWriteCalls = m.batchWriteCalls[1:]
+	return c.output, c.err
+}
+
+func (m *ddbMock) MockBatchWrite(i *dynamodb.BatchWriteItemInput, o *dynamodb.BatchWriteItemOutput, err ...error) {
+	c := batchWriteCall{input: i, output: o}
+	if len(err) > 0 {
+		c.err = err[0]
+	}
+	m.batchWriteCalls = append(m.batchWriteCalls, c)
 }
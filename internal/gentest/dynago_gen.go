@@ -0,0 +1,1231 @@
+// Code generated by dynago-gen. DO NOT EDIT.
+
+package gentest
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/twharmon/dynago"
+)
+
+// MarshalDynago converts the Comment into a DynamoDB item.
+func (x Comment) MarshalDynago() (map[string]*dynamodb.AttributeValue, error) {
+	item := make(map[string]*dynamodb.AttributeValue, 4)
+	{
+		var av *dynamodb.AttributeValue
+		var b strings.Builder
+		b.WriteString("Post#")
+		var s1 string
+		{
+			pv := x.PostID
+			s1 = string(pv)
+		}
+		b.WriteString(s1)
+		s := b.String()
+		av = &dynamodb.AttributeValue{S: &s}
+		if av != nil {
+			item["PK"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		var b strings.Builder
+		b.WriteString("Comment#")
+		var s2 string
+		{
+			pv := x.At
+			s2 = dynago.FormatTime(pv, "unix")
+		}
+		b.WriteString(s2)
+		s := b.String()
+		av = &dynamodb.AttributeValue{S: &s}
+		if av != nil {
+			item["SK"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		fv := x.Body
+		v := fv
+		s := string(v)
+		av = &dynamodb.AttributeValue{S: &s}
+		if av != nil {
+			item["Body"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		fv := x.Likes
+		if fv != nil {
+			v := *fv
+			{
+				s := strconv.FormatUint(uint64(v), 10)
+				av = &dynamodb.AttributeValue{N: &s}
+			}
+		}
+		if av != nil {
+			item["Likes"] = av
+		}
+	}
+	return item, nil
+}
+
+// UnmarshalDynago converts a DynamoDB item into the Comment.
+func (x *Comment) UnmarshalDynago(item map[string]*dynamodb.AttributeValue) error {
+	if av := item["PK"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero string
+			x.PostID = zero
+		} else {
+			if av.S != nil {
+				if values, ok := dynago.ScanFmt(*av.S, []string{"Post#", ""}, []bool{false, true}); ok {
+					{
+						str := values[1]
+						x.PostID = string(str)
+					}
+				}
+			}
+		}
+	}
+	if av := item["SK"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero time.Time
+			x.At = zero
+		} else {
+			if av.S != nil {
+				if values, ok := dynago.ScanFmt(*av.S, []string{"Comment#", ""}, []bool{false, true}); ok {
+					{
+						str := values[1]
+						t, err := dynago.ParseTime(str, "unix")
+						if err != nil {
+							return fmt.Errorf("parse: field At: parseTime: %s", err)
+						}
+						x.At = t
+					}
+				}
+			}
+		}
+	}
+	if av := item["Body"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero string
+			x.Body = zero
+		} else {
+			if av.S != nil {
+				x.Body = string(*av.S)
+			}
+		}
+	}
+	if av := item["Likes"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero *uint
+			x.Likes = zero
+		} else {
+			if x.Likes == nil {
+				x.Likes = new(uint)
+			}
+			if av.N != nil {
+				n, err := strconv.ParseUint(*av.N, 10, 0)
+				if err != nil {
+					return err
+				}
+				(*x.Likes) = uint(n)
+			}
+		}
+	}
+	return nil
+}
+
+// DynagoConfig returns the configuration the methods of the Comment were
+// generated for.
+func (*Comment) DynagoConfig() dynago.GeneratedConfig {
+	c := dynagoConfig
+	c.Type = reflect.TypeOf((*Comment)(nil)).Elem()
+	return c
+}
+
+// MarshalDynago converts the Post into a DynamoDB item.
+func (x Post) MarshalDynago() (map[string]*dynamodb.AttributeValue, error) {
+	item := make(map[string]*dynamodb.AttributeValue, 30)
+	{
+		var av *dynamodb.AttributeValue
+		var b strings.Builder
+		var s3 string
+		{
+			pv := x.Base.Kind
+			s3 = string(pv)
+		}
+		s3 = dynago.FmtValue(s3, 0, false, "#")
+		b.WriteString(s3)
+		b.WriteString("#")
+		var s4 string
+		{
+			pv := x.Base.ID
+			s4 = string(pv)
+		}
+		b.WriteString(s4)
+		s := b.String()
+		av = &dynamodb.AttributeValue{S: &s}
+		if av != nil {
+			item["PK"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		var b strings.Builder
+		var s5 string
+		{
+			pv := x.Base.Created
+			s5 = dynago.FormatTime(pv, "2006-01-02T15:04:05Z07:00")
+		}
+		b.WriteString(s5)
+		s := b.String()
+		av = &dynamodb.AttributeValue{S: &s}
+		if av != nil {
+			item["Created"] = av
+		}
+	}
+	{
+		if x.Audit != nil {
+			var av *dynamodb.AttributeValue
+			fv := x.Audit.UpdatedBy
+			v := fv
+			s := string(v)
+			av = &dynamodb.AttributeValue{S: &s}
+			if av != nil {
+				item["UpdatedBy"] = av
+			}
+		}
+	}
+	{
+		if x.Audit != nil {
+			var av *dynamodb.AttributeValue
+			fv := x.Audit.Reviewers
+			if !(len(fv) == 0) {
+				v := fv
+				{
+					l := &dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{}}
+					for _, el6 := range v {
+						var av7 *dynamodb.AttributeValue
+						{
+							s := string(el6)
+							av7 = &dynamodb.AttributeValue{S: &s}
+						}
+						l.L = append(l.L, av7)
+					}
+					av = l
+				}
+			}
+			if av != nil {
+				item["Reviewers"] = av
+			}
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		var b strings.Builder
+		b.WriteString("Post#")
+		var s8 string
+		{
+			pv := x.Seq
+			s8 = strconv.FormatInt(int64(pv), 10)
+		}
+		s8 = dynago.FmtValue(s8, 6, true, "#")
+		b.WriteString(s8)
+		b.WriteString("#")
+		var s9 string
+		{
+			pv := x.Status
+			b, err := pv.MarshalText()
+			if err != nil {
+				return nil, fmt.Errorf("dynago: attribute SK: MarshalText: %w", err)
+			}
+			s9 = string(b)
+		}
+		b.WriteString(s9)
+		s := b.String()
+		av = &dynamodb.AttributeValue{S: &s}
+		if av != nil {
+			item["SK"] = av
+			item["GSI1SK"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		fv := x.Status
+		if !(fv == 0) {
+			v := fv
+			b, err := v.MarshalText()
+			if err != nil {
+				return nil, fmt.Errorf("dynago: attribute Status: MarshalText: %w", err)
+			}
+			s := string(b)
+			av = &dynamodb.AttributeValue{S: &s}
+		}
+		if av != nil {
+			item["Status"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		fv := x.Title
+		v := fv
+		s := string(v)
+		av = &dynamodb.AttributeValue{S: &s}
+		if av != nil {
+			item["Title"] = av
+			item["GSI1PK"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		fv := x.Subtitle
+		if fv != nil {
+			v := *fv
+			s := string(v)
+			av = &dynamodb.AttributeValue{S: &s}
+		} else {
+			av = &dynamodb.AttributeValue{NULL: dynagoBool(true)}
+		}
+		if av != nil {
+			item["Subtitle"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		fv := x.Draft
+		if !(!bool(fv)) {
+			v := fv
+			{
+				b := bool(v)
+				av = &dynamodb.AttributeValue{BOOL: &b}
+			}
+		}
+		if av != nil {
+			item["Draft"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		fv := x.Score
+		v := fv
+		{
+			s := strconv.FormatFloat(float64(v), 'f', -1, 64)
+			av = &dynamodb.AttributeValue{N: &s}
+		}
+		if av != nil {
+			item["Score"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		fv := x.Ratio
+		if fv != nil {
+			v := *fv
+			{
+				s := strconv.FormatFloat(float64(v), 'f', -1, 32)
+				av = &dynamodb.AttributeValue{N: &s}
+			}
+		}
+		if av != nil {
+			item["Ratio"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		fv := x.Views
+		v := fv
+		{
+			s := strconv.FormatUint(uint64(v), 10)
+			av = &dynamodb.AttributeValue{N: &s}
+		}
+		if av != nil {
+			item["Views"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		fv := x.Delta
+		v := fv
+		{
+			s := strconv.FormatInt(int64(v), 10)
+			av = &dynamodb.AttributeValue{N: &s}
+		}
+		if av != nil {
+			item["Delta"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		fv := x.Tags
+		v := fv
+		set := &dynamodb.AttributeValue{}
+		seen := make(map[string]bool)
+		for _, el := range v {
+			s := string(el)
+			if !seen[s] {
+				set.SS = append(set.SS, &s)
+			}
+			seen[s] = true
+		}
+		if len(set.SS) > 0 {
+			av = set
+		}
+		if av != nil {
+			item["Tags"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		fv := x.Ratings
+		v := fv
+		set := &dynamodb.AttributeValue{}
+		seen := make(map[string]bool)
+		for _, el := range v {
+			s := strconv.FormatInt(int64(el), 10)
+			if !seen[s] {
+				set.NS = append(set.NS, &s)
+			}
+			seen[s] = true
+		}
+		if len(set.NS) > 0 {
+			av = set
+		}
+		if av != nil {
+			item["Ratings"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		fv := x.Lines
+		v := fv
+		{
+			l := &dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{}}
+			for _, el10 := range v {
+				var av11 *dynamodb.AttributeValue
+				{
+					s := string(el10)
+					av11 = &dynamodb.AttributeValue{S: &s}
+				}
+				l.L = append(l.L, av11)
+			}
+			av = l
+		}
+		if av != nil {
+			item["Lines"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		fv := x.Blobs
+		v := fv
+		set := &dynamodb.AttributeValue{}
+		seen := make(map[string]bool)
+		for _, el := range v {
+			if !seen[string(el)] {
+				set.BS = append(set.BS, []byte(el))
+			}
+			seen[string(el)] = true
+		}
+		if len(set.BS) > 0 {
+			av = set
+		}
+		if av != nil {
+			item["Blobs"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		fv := x.Data
+		v := fv
+		av = &dynamodb.AttributeValue{B: []byte(v)}
+		if av != nil {
+			item["Data"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		fv := x.Price
+		v := fv
+		var err error
+		av, err = v.MarshalAttribute()
+		if err != nil {
+			return nil, fmt.Errorf("dynago: attribute Price: MarshalAttribute: %w", err)
+		}
+		if av != nil {
+			item["Price"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		fv := x.Refund
+		if fv != nil {
+			v := *fv
+			var err error
+			av, err = v.MarshalAttribute()
+			if err != nil {
+				return nil, fmt.Errorf("dynago: attribute Refund: MarshalAttribute: %w", err)
+			}
+		}
+		if av != nil {
+			item["Refund"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		fv := x.Owner
+		if fv != nil {
+			v := *fv
+			b, err := v.MarshalText()
+			if err != nil {
+				return nil, fmt.Errorf("dynago: attribute Owner: MarshalText: %w", err)
+			}
+			s := string(b)
+			av = &dynamodb.AttributeValue{S: &s}
+		}
+		if av != nil {
+			item["Owner"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		fv := x.At
+		v := fv
+		{
+			s := dynago.FormatTime(v, "unixmilli")
+			av = &dynamodb.AttributeValue{N: &s}
+		}
+		if av != nil {
+			item["At"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		fv := x.Day
+		if !(fv.IsZero()) {
+			var b strings.Builder
+			var s12 string
+			{
+				pv := x.Day
+				s12 = dynago.FormatTime(pv, "2006-01-02")
+			}
+			b.WriteString(s12)
+			s := b.String()
+			av = &dynamodb.AttributeValue{S: &s}
+		}
+		if av != nil {
+			item["Day"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		fv := x.Seen
+		if fv != nil {
+			v := *fv
+			{
+				s := dynago.FormatTime(v, "unix")
+				av = &dynamodb.AttributeValue{N: &s}
+			}
+		}
+		if av != nil {
+			item["Seen"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		fv := x.Times
+		v := fv
+		{
+			l := &dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{}}
+			for _, el13 := range v {
+				var av14 *dynamodb.AttributeValue
+				{
+					s := dynago.FormatTime(el13, "2006-01-02T15:04:05Z07:00")
+					av14 = &dynamodb.AttributeValue{S: &s}
+				}
+				l.L = append(l.L, av14)
+			}
+			av = l
+		}
+		if av != nil {
+			item["Times"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		fv := x.Expires
+		v := fv
+		if !v.IsZero() {
+			{
+				s := dynago.FormatTime(v, "unix")
+				av = &dynamodb.AttributeValue{N: &s}
+			}
+		}
+		if av != nil {
+			item["Expires"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		fv := x.Version
+		v := fv
+		{
+			s := strconv.FormatInt(int64(v), 10)
+			av = &dynamodb.AttributeValue{N: &s}
+		}
+		if av != nil {
+			item["Version"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		var b strings.Builder
+		var s15 string
+		{
+			pv := x.Path
+			s15 = string(pv)
+		}
+		s15 = dynago.FmtValue(s15, 0, false, "/")
+		b.WriteString(s15)
+		b.WriteString("/")
+		var s16 string
+		{
+			pv := x.Title
+			s16 = string(pv)
+		}
+		b.WriteString(s16)
+		s := b.String()
+		av = &dynamodb.AttributeValue{S: &s}
+		if av != nil {
+			item["Path"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		var b strings.Builder
+		b.WriteString("x")
+		var s17 string
+		{
+			pv := x.Code
+			s17 = string(pv)
+		}
+		s17 = dynago.FmtValue(s17, 5, false, "")
+		b.WriteString(s17)
+		s := b.String()
+		av = &dynamodb.AttributeValue{S: &s}
+		if av != nil {
+			item["Code"] = av
+		}
+	}
+	{
+		var av *dynamodb.AttributeValue
+		fv := x.Flags
+		v := fv
+		{
+			l := &dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{}}
+			for _, el18 := range v {
+				var av19 *dynamodb.AttributeValue
+				{
+					b := bool(el18)
+					av19 = &dynamodb.AttributeValue{BOOL: &b}
+				}
+				l.L = append(l.L, av19)
+			}
+			av = l
+		}
+		if av != nil {
+			item["Flags"] = av
+		}
+	}
+	return item, nil
+}
+
+// UnmarshalDynago converts a DynamoDB item into the Post.
+func (x *Post) UnmarshalDynago(item map[string]*dynamodb.AttributeValue) error {
+	if av := item["PK"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero string
+			x.Base.ID = zero
+		} else {
+			if av.S != nil {
+				if values, ok := dynago.ScanFmt(*av.S, []string{"", "#", ""}, []bool{true, false, true}); ok {
+					{
+						str := values[0]
+						x.Base.Kind = string(str)
+					}
+					{
+						str := values[2]
+						x.Base.ID = string(str)
+					}
+				}
+			}
+		}
+	}
+	if av := item["Created"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero time.Time
+			x.Base.Created = zero
+		} else {
+			if av.S != nil {
+				if values, ok := dynago.ScanFmt(*av.S, []string{""}, []bool{true}); ok {
+					{
+						str := values[0]
+						t, err := dynago.ParseTime(str, "2006-01-02T15:04:05Z07:00")
+						if err != nil {
+							return fmt.Errorf("parse: field Created: parseTime: %s", err)
+						}
+						x.Base.Created = t
+					}
+				}
+			} else if av.N != nil {
+				return fmt.Errorf("dynago: type mismatch: can not unmarshal N into %T with layout %q", x.Base.Created, "2006-01-02T15:04:05Z07:00")
+			}
+		}
+	}
+	if av := item["UpdatedBy"]; av != nil {
+		if x.Audit == nil {
+			x.Audit = new(Audit)
+		}
+		if av.NULL != nil && *av.NULL {
+			var zero string
+			x.Audit.UpdatedBy = zero
+		} else {
+			if av.S != nil {
+				x.Audit.UpdatedBy = string(*av.S)
+			}
+		}
+	}
+	if av := item["Reviewers"]; av != nil {
+		if x.Audit == nil {
+			x.Audit = new(Audit)
+		}
+		if av.NULL != nil && *av.NULL {
+			var zero []string
+			x.Audit.Reviewers = zero
+		} else {
+			sl20 := make([]string, len(av.L))
+			for i22, el21 := range av.L {
+				if el21 == nil || (el21.NULL != nil && *el21.NULL) {
+					continue
+				}
+				if el21.S != nil {
+					sl20[i22] = string(*el21.S)
+				}
+			}
+			x.Audit.Reviewers = sl20
+		}
+	}
+	if av := item["SK"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero int
+			x.Seq = zero
+		} else {
+			if av.S != nil {
+				if values, ok := dynago.ScanFmt(*av.S, []string{"Post#", "", "#", ""}, []bool{false, true, false, true}); ok {
+					{
+						str := values[1]
+						n, err := strconv.ParseInt(str, 10, 0)
+						if err != nil {
+							return fmt.Errorf("parse: field Seq: %s", err)
+						}
+						x.Seq = int(n)
+					}
+					{
+						str := values[3]
+						err := x.Status.UnmarshalText([]byte(str))
+						if err != nil {
+							return fmt.Errorf("parse: field Status: UnmarshalText: %s", err)
+						}
+					}
+				}
+			}
+		}
+	}
+	if av := item["Status"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero Status
+			x.Status = zero
+		} else {
+			if av.S != nil {
+				if err := x.Status.UnmarshalText([]byte(*av.S)); err != nil {
+					return fmt.Errorf("UnmarshalText: %w", err)
+				}
+			}
+		}
+	}
+	if av := item["Title"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero string
+			x.Title = zero
+		} else {
+			if av.S != nil {
+				x.Title = string(*av.S)
+			}
+		}
+	}
+	if av := item["Subtitle"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero *string
+			x.Subtitle = zero
+		} else {
+			if x.Subtitle == nil {
+				x.Subtitle = new(string)
+			}
+			if av.S != nil {
+				(*x.Subtitle) = string(*av.S)
+			}
+		}
+	}
+	if av := item["Draft"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero bool
+			x.Draft = zero
+		} else {
+			if av.BOOL != nil {
+				x.Draft = bool(*av.BOOL)
+			}
+		}
+	}
+	if av := item["Score"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero float64
+			x.Score = zero
+		} else {
+			if av.N != nil {
+				n, err := strconv.ParseFloat(*av.N, 64)
+				if err != nil {
+					return err
+				}
+				x.Score = float64(n)
+			}
+		}
+	}
+	if av := item["Ratio"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero *float32
+			x.Ratio = zero
+		} else {
+			if x.Ratio == nil {
+				x.Ratio = new(float32)
+			}
+			if av.N != nil {
+				n, err := strconv.ParseFloat(*av.N, 32)
+				if err != nil {
+					return err
+				}
+				(*x.Ratio) = float32(n)
+			}
+		}
+	}
+	if av := item["Views"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero uint16
+			x.Views = zero
+		} else {
+			if av.N != nil {
+				n, err := strconv.ParseUint(*av.N, 10, 16)
+				if err != nil {
+					return err
+				}
+				x.Views = uint16(n)
+			}
+		}
+	}
+	if av := item["Delta"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero int8
+			x.Delta = zero
+		} else {
+			if av.N != nil {
+				n, err := strconv.ParseInt(*av.N, 10, 8)
+				if err != nil {
+					return err
+				}
+				x.Delta = int8(n)
+			}
+		}
+	}
+	if av := item["Tags"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero []string
+			x.Tags = zero
+		} else {
+			var els []*dynamodb.AttributeValue
+			for _, s := range av.SS {
+				els = append(els, &dynamodb.AttributeValue{S: s})
+			}
+			for _, n := range av.NS {
+				els = append(els, &dynamodb.AttributeValue{N: n})
+			}
+			for _, b := range av.BS {
+				els = append(els, &dynamodb.AttributeValue{B: b})
+			}
+			if len(els) == 0 && av.L != nil {
+				els = av.L
+			}
+			sl23 := make([]string, len(els))
+			for i25, el24 := range els {
+				if el24 == nil || (el24.NULL != nil && *el24.NULL) {
+					continue
+				}
+				if el24.S != nil {
+					sl23[i25] = string(*el24.S)
+				}
+			}
+			x.Tags = sl23
+		}
+	}
+	if av := item["Ratings"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero []int
+			x.Ratings = zero
+		} else {
+			var els []*dynamodb.AttributeValue
+			for _, s := range av.SS {
+				els = append(els, &dynamodb.AttributeValue{S: s})
+			}
+			for _, n := range av.NS {
+				els = append(els, &dynamodb.AttributeValue{N: n})
+			}
+			for _, b := range av.BS {
+				els = append(els, &dynamodb.AttributeValue{B: b})
+			}
+			if len(els) == 0 && av.L != nil {
+				els = av.L
+			}
+			sl26 := make([]int, len(els))
+			for i28, el27 := range els {
+				if el27 == nil || (el27.NULL != nil && *el27.NULL) {
+					continue
+				}
+				if el27.N != nil {
+					n, err := strconv.ParseInt(*el27.N, 10, 0)
+					if err != nil {
+						return err
+					}
+					sl26[i28] = int(n)
+				}
+			}
+			x.Ratings = sl26
+		}
+	}
+	if av := item["Lines"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero []string
+			x.Lines = zero
+		} else {
+			sl29 := make([]string, len(av.L))
+			for i31, el30 := range av.L {
+				if el30 == nil || (el30.NULL != nil && *el30.NULL) {
+					continue
+				}
+				if el30.S != nil {
+					sl29[i31] = string(*el30.S)
+				}
+			}
+			x.Lines = sl29
+		}
+	}
+	if av := item["Blobs"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero [][]byte
+			x.Blobs = zero
+		} else {
+			var els []*dynamodb.AttributeValue
+			for _, s := range av.SS {
+				els = append(els, &dynamodb.AttributeValue{S: s})
+			}
+			for _, n := range av.NS {
+				els = append(els, &dynamodb.AttributeValue{N: n})
+			}
+			for _, b := range av.BS {
+				els = append(els, &dynamodb.AttributeValue{B: b})
+			}
+			if len(els) == 0 && av.L != nil {
+				els = av.L
+			}
+			sl32 := make([][]byte, len(els))
+			for i34, el33 := range els {
+				if el33 == nil || (el33.NULL != nil && *el33.NULL) {
+					continue
+				}
+				if el33.B != nil {
+					sl32[i34] = []byte(el33.B)
+				}
+			}
+			x.Blobs = sl32
+		}
+	}
+	if av := item["Data"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero []byte
+			x.Data = zero
+		} else {
+			if av.B != nil {
+				x.Data = []byte(av.B)
+			}
+		}
+	}
+	if av := item["Price"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero Money
+			x.Price = zero
+		} else {
+			if err := x.Price.UnmarshalAttribute(av); err != nil {
+				return fmt.Errorf("UnmarshalAttribute: %w", err)
+			}
+		}
+	}
+	if av := item["Refund"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero *Money
+			x.Refund = zero
+		} else {
+			if x.Refund == nil {
+				x.Refund = new(Money)
+			}
+			if err := (*x.Refund).UnmarshalAttribute(av); err != nil {
+				return fmt.Errorf("UnmarshalAttribute: %w", err)
+			}
+		}
+	}
+	if av := item["Owner"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero *Status
+			x.Owner = zero
+		} else {
+			if x.Owner == nil {
+				x.Owner = new(Status)
+			}
+			if av.S != nil {
+				if err := (*x.Owner).UnmarshalText([]byte(*av.S)); err != nil {
+					return fmt.Errorf("UnmarshalText: %w", err)
+				}
+			}
+		}
+	}
+	if av := item["At"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero time.Time
+			x.At = zero
+		} else {
+			if av.N != nil {
+				t, err := dynago.ParseTime(*av.N, "unixmilli")
+				if err != nil {
+					return err
+				}
+				x.At = t
+			} else if av.S != nil {
+				t, err := dynago.ParseTime(*av.S, "unixmilli")
+				if err != nil {
+					return err
+				}
+				x.At = t
+			}
+		}
+	}
+	if av := item["Day"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero time.Time
+			x.Day = zero
+		} else {
+			if av.S != nil {
+				if values, ok := dynago.ScanFmt(*av.S, []string{""}, []bool{true}); ok {
+					{
+						str := values[0]
+						t, err := dynago.ParseTime(str, "2006-01-02")
+						if err != nil {
+							return fmt.Errorf("parse: field Day: parseTime: %s", err)
+						}
+						x.Day = t
+					}
+				}
+			} else if av.N != nil {
+				return fmt.Errorf("dynago: type mismatch: can not unmarshal N into %T with layout %q", x.Day, "2006-01-02")
+			}
+		}
+	}
+	if av := item["Seen"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero *time.Time
+			x.Seen = zero
+		} else {
+			if x.Seen == nil {
+				x.Seen = new(time.Time)
+			}
+			if av.N != nil {
+				t, err := dynago.ParseTime(*av.N, "unix")
+				if err != nil {
+					return err
+				}
+				(*x.Seen) = t
+			} else if av.S != nil {
+				t, err := dynago.ParseTime(*av.S, "unix")
+				if err != nil {
+					return err
+				}
+				(*x.Seen) = t
+			}
+		}
+	}
+	if av := item["Times"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero []time.Time
+			x.Times = zero
+		} else {
+			sl35 := make([]time.Time, len(av.L))
+			for i37, el36 := range av.L {
+				if el36 == nil || (el36.NULL != nil && *el36.NULL) {
+					continue
+				}
+				if el36.N != nil {
+					return fmt.Errorf("dynago: type mismatch: can not unmarshal N into %T with layout %q", sl35[i37], "2006-01-02T15:04:05Z07:00")
+				} else if el36.S != nil {
+					t, err := dynago.ParseTime(*el36.S, "2006-01-02T15:04:05Z07:00")
+					if err != nil {
+						return err
+					}
+					sl35[i37] = t
+				}
+			}
+			x.Times = sl35
+		}
+	}
+	if av := item["Expires"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero time.Time
+			x.Expires = zero
+		} else {
+			if av.N != nil {
+				t, err := dynago.ParseTime(*av.N, "unix")
+				if err != nil {
+					return err
+				}
+				x.Expires = t
+			} else if av.S != nil {
+				t, err := dynago.ParseTime(*av.S, "unix")
+				if err != nil {
+					return err
+				}
+				x.Expires = t
+			}
+		}
+	}
+	if av := item["Version"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero int
+			x.Version = zero
+		} else {
+			if av.N != nil {
+				n, err := strconv.ParseInt(*av.N, 10, 0)
+				if err != nil {
+					return err
+				}
+				x.Version = int(n)
+			}
+		}
+	}
+	if av := item["Path"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero string
+			x.Path = zero
+		} else {
+			if av.S != nil {
+				if values, ok := dynago.ScanFmt(*av.S, []string{"", "/", ""}, []bool{true, false, true}); ok {
+					{
+						str := values[0]
+						x.Path = string(str)
+					}
+					{
+						str := values[2]
+						x.Title = string(str)
+					}
+				}
+			}
+		}
+	}
+	if av := item["Code"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero string
+			x.Code = zero
+		} else {
+			if av.S != nil {
+				if values, ok := dynago.ScanFmt(*av.S, []string{"x", ""}, []bool{false, true}); ok {
+					{
+						str := values[1]
+						str = strings.TrimLeft(str, " ")
+						x.Code = string(str)
+					}
+				}
+			}
+		}
+	}
+	if av := item["Flags"]; av != nil {
+		if av.NULL != nil && *av.NULL {
+			var zero []bool
+			x.Flags = zero
+		} else {
+			sl38 := make([]bool, len(av.L))
+			for i40, el39 := range av.L {
+				if el39 == nil || (el39.NULL != nil && *el39.NULL) {
+					continue
+				}
+				if el39.BOOL != nil {
+					sl38[i40] = bool(*el39.BOOL)
+				}
+			}
+			x.Flags = sl38
+		}
+	}
+	return nil
+}
+
+// DynagoConfig returns the configuration the methods of the Post were
+// generated for.
+func (*Post) DynagoConfig() dynago.GeneratedConfig {
+	c := dynagoConfig
+	c.Type = reflect.TypeOf((*Post)(nil)).Elem()
+	return c
+}
+
+// MarshalDynago converts the Table into a DynamoDB item.
+func (x Table) MarshalDynago() (map[string]*dynamodb.AttributeValue, error) {
+	item := make(map[string]*dynamodb.AttributeValue, 0)
+	return item, nil
+}
+
+// UnmarshalDynago converts a DynamoDB item into the Table.
+func (x *Table) UnmarshalDynago(item map[string]*dynamodb.AttributeValue) error {
+	return nil
+}
+
+// DynagoConfig returns the configuration the methods of the Table were
+// generated for.
+func (*Table) DynagoConfig() dynago.GeneratedConfig {
+	c := dynagoConfig
+	c.Type = reflect.TypeOf((*Table)(nil)).Elem()
+	return c
+}
+
+// dynagoConfig is the configuration the methods were generated for.
+var dynagoConfig = dynago.GeneratedConfig{
+	AttrTagName:        "attr",
+	FmtTagName:         "fmt",
+	TypeTagName:        "type",
+	LayoutTagName:      "layout",
+	AttrsToCopyTagName: "copy",
+	VersionTagName:     "version",
+	AutoTagName:        "auto",
+	TTLTagName:         "ttl",
+	DefaultOmitEmpty:   false,
+	NullPointers:       false,
+}
+
+func dynagoBool(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,130 @@
+// Package gentest holds structs with methods generated by dynago-gen,
+// which are tested against the reflective path of dynago.
+package gentest
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+//go:generate go run ../../cmd/dynago-gen
+
+type Table struct{}
+
+func (t *Table) PrimaryKeys() []string {
+	return []string{"PK", "SK"}
+}
+
+type Money struct {
+	Cents int64
+}
+
+func (m Money) MarshalAttribute() (*dynamodb.AttributeValue, error) {
+	s := fmt.Sprintf("%d.%02d", m.Cents/100, m.Cents%100)
+	return &dynamodb.AttributeValue{N: &s}, nil
+}
+
+func (m *Money) UnmarshalAttribute(av *dynamodb.AttributeValue) error {
+	if av.N == nil {
+		return errors.New("money must be a number")
+	}
+	dollars, cents, _ := strings.Cut(*av.N, ".")
+	d, err := strconv.ParseInt(dollars, 10, 64)
+	if err != nil {
+		return err
+	}
+	c, err := strconv.ParseInt(cents, 10, 64)
+	if err != nil {
+		return err
+	}
+	m.Cents = d*100 + c
+	return nil
+}
+
+type Status int
+
+func (s Status) MarshalText() ([]byte, error) {
+	switch s {
+	case 0:
+		return []byte("draft"), nil
+	case 1:
+		return []byte("published"), nil
+	}
+	return nil, fmt.Errorf("invalid status %d", s)
+}
+
+func (s *Status) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case "draft":
+		*s = 0
+	case "published":
+		*s = 1
+	default:
+		return fmt.Errorf("invalid status %s", b)
+	}
+	return nil
+}
+
+type Base struct {
+	ID      string    `attr:"PK" fmt:"{Kind}#{}"`
+	Kind    string    `attr:"-"`
+	Created time.Time `auto:"create"`
+}
+
+type Audit struct {
+	UpdatedBy string
+	Reviewers []string `attr:",omitempty"`
+}
+
+type Post struct {
+	*Table
+	Base
+	*Audit
+	Seq      int     `attr:"SK" fmt:"Post#{:06d}#{Status}" copy:"GSI1SK"`
+	Status   Status  `attr:",omitempty"`
+	Title    string  `copy:"GSI1PK"`
+	Subtitle *string `attr:",null"`
+	Draft    bool    `attr:",omitempty"`
+	Score    float64
+	Ratio    *float32
+	Views    uint16
+	Delta    int8
+	Tags     []string `type:"SS"`
+	Ratings  []int    `type:"NS"`
+	Lines    []string
+	Blobs    [][]byte `type:"BS"`
+	Data     []byte
+	Price    Money
+	Refund   *Money
+	Owner    *Status
+	At       time.Time  `layout:"unixmilli"`
+	Day      time.Time  `layout:"2006-01-02" attr:",omitempty"`
+	Seen     *time.Time `type:"N"`
+	Times    []time.Time
+	Expires  time.Time `ttl:""`
+	Version  int       `version:""`
+	Path     string    `fmt:"{}/{Title}"`
+	Code     string    `fmt:"x{:5}"`
+	Flags    []bool
+	internal string
+}
+
+type Comment struct {
+	*Table
+	PostID string    `attr:"PK" fmt:"Post#{}"`
+	At     time.Time `attr:"SK" fmt:"Comment#{:d}" layout:"unix"`
+	Body   string
+	Likes  *uint
+}
+
+// Meta is skipped by the generator since it has a map.
+type Meta struct {
+	*Table
+	ID     string `attr:"PK"`
+	Values map[string]string
+}
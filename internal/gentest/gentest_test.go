@@ -0,0 +1,272 @@
+package gentest_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/twharmon/dynago"
+	"github.com/twharmon/dynago/internal/gentest"
+)
+
+var reflective = dynago.New(nil)
+
+// post and comment have the fields of gentest.Post and gentest.Comment
+// but not their generated methods, so they are marshalled with
+// reflection.
+type (
+	post    gentest.Post
+	comment gentest.Comment
+)
+
+func assertEq(t *testing.T, want, got interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("want: %v\n got: %v", want, got)
+	}
+}
+
+func posts() []gentest.Post {
+	at := time.Date(2024, 3, 4, 5, 6, 7, 8000000, time.UTC)
+	subtitle := "sub/title"
+	ratio := float32(0.25)
+	owner := gentest.Status(1)
+	return []gentest.Post{
+		{},
+		{
+			Base:     gentest.Base{ID: "a#b\\c", Kind: "Post", Created: at},
+			Audit:    &gentest.Audit{UpdatedBy: "gopher", Reviewers: []string{"x", "y"}},
+			Seq:      -42,
+			Status:   1,
+			Title:    "Hello/World",
+			Subtitle: &subtitle,
+			Draft:    true,
+			Score:    1.5,
+			Ratio:    &ratio,
+			Views:    65535,
+			Delta:    -128,
+			Tags:     []string{"b", "a", "b"},
+			Ratings:  []int{5, 3, 5},
+			Lines:    []string{"one", "two"},
+			Blobs:    [][]byte{{1}, {2}, {1}},
+			Data:     []byte{1, 2, 3},
+			Price:    gentest.Money{Cents: 1050},
+			Refund:   &gentest.Money{Cents: 99},
+			Owner:    &owner,
+			At:       at,
+			Day:      at,
+			Seen:     &at,
+			Times:    []time.Time{at, at.Add(time.Hour)},
+			Expires:  at,
+			Version:  3,
+			Path:     "a/b",
+			Code:     "ab",
+			Flags:    []bool{true, false},
+		},
+		{
+			Audit: &gentest.Audit{},
+			Tags:  []string{},
+			Lines: []string{},
+		},
+	}
+}
+
+func TestMarshalGenerated(t *testing.T) {
+	for _, p := range posts() {
+		want, err := reflective.Marshal((*post)(&p))
+		if err != nil {
+			t.Fatalf("unexpected err: %s", err)
+		}
+		got, err := p.MarshalDynago()
+		if err != nil {
+			t.Fatalf("unexpected err: %s", err)
+		}
+		assertEq(t, want, got)
+	}
+}
+
+func TestUnmarshalGenerated(t *testing.T) {
+	for _, p := range posts() {
+		item, err := reflective.Marshal((*post)(&p))
+		if err != nil {
+			t.Fatalf("unexpected err: %s", err)
+		}
+		var want, got gentest.Post
+		if err := reflective.Unmarshal(item, (*post)(&want)); err != nil {
+			t.Fatalf("unexpected err: %s", err)
+		}
+		if err := got.UnmarshalDynago(item); err != nil {
+			t.Fatalf("unexpected err: %s", err)
+		}
+		assertEq(t, want, got)
+	}
+}
+
+func TestUnmarshalGeneratedItems(t *testing.T) {
+	null := &dynamodb.AttributeValue{NULL: aws.Bool(true)}
+	items := []map[string]*dynamodb.AttributeValue{
+		{
+			"PK":        {S: aws.String("Post#a\\#b")},
+			"SK":        {S: aws.String("Post#  -42#published")},
+			"Subtitle":  null,
+			"Ratio":     null,
+			"Title":     null,
+			"Tags":      {L: []*dynamodb.AttributeValue{{S: aws.String("x")}, null}},
+			"Ratings":   {NS: []*string{aws.String("1"), aws.String("2")}},
+			"Lines":     {S: aws.String("not a list")},
+			"Created":   {S: aws.String("2023-11-14T22:13:20Z")},
+			"Seen":      {S: aws.String("1700000000")},
+			"Day":       {S: aws.String("2024-03-04")},
+			"Path":      {S: aws.String("a\\/b/c")},
+			"Code":      {S: aws.String("x   ab")},
+			"Owner":     {S: aws.String("draft")},
+			"Refund":    {N: aws.String("1.00")},
+			"UpdatedBy": {S: aws.String("gopher")},
+		},
+		{
+			"PK":   {S: aws.String("mismatch")},
+			"SK":   {S: aws.String("Post#x")},
+			"Path": {S: aws.String("no separator")},
+		},
+	}
+	for _, item := range items {
+		var want, got gentest.Post
+		wantErr := reflective.Unmarshal(item, (*post)(&want))
+		gotErr := got.UnmarshalDynago(item)
+		if (wantErr == nil) != (gotErr == nil) {
+			t.Fatalf("want err: %v\n got err: %v", wantErr, gotErr)
+		}
+		assertEq(t, want, got)
+	}
+}
+
+func TestUnmarshalGeneratedErr(t *testing.T) {
+	items := []map[string]*dynamodb.AttributeValue{
+		{"SK": {S: aws.String("Post#1#unknown")}},
+		{"Score": {N: aws.String("x")}},
+		{"Price": {S: aws.String("x")}},
+		{"At": {N: aws.String("x")}},
+		{"Created": {N: aws.String("1700000000")}},
+		{"Day": {N: aws.String("1700000000")}},
+		{"Times": {L: []*dynamodb.AttributeValue{{N: aws.String("1700000000")}}}},
+	}
+	for _, item := range items {
+		var want, got gentest.Post
+		wantErr := reflective.Unmarshal(item, (*post)(&want))
+		gotErr := got.UnmarshalDynago(item)
+		if wantErr == nil || gotErr == nil {
+			t.Fatalf("want err: %v\n got err: %v", wantErr, gotErr)
+		}
+		assertEq(t, wantErr.Error(), gotErr.Error())
+	}
+}
+
+func TestComment(t *testing.T) {
+	likes := uint(7)
+	c := gentest.Comment{
+		PostID: "p1",
+		At:     time.Unix(1700000000, 0).UTC(),
+		Body:   "Hi",
+		Likes:  &likes,
+	}
+	want, err := reflective.Marshal((*comment)(&c))
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	got, err := c.MarshalDynago()
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, want, got)
+	var wantC, gotC gentest.Comment
+	if err := reflective.Unmarshal(want, (*comment)(&wantC)); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	if err := gotC.UnmarshalDynago(want); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, wantC, gotC)
+}
+
+func TestMarshalUsesGenerated(t *testing.T) {
+	client := dynago.New(nil)
+	p := posts()[1]
+	want, err := p.MarshalDynago()
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	got, err := client.Marshal(&p)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, want, got)
+	allocs := testing.AllocsPerRun(10, func() {
+		if _, err := client.Marshal(&p); err != nil {
+			t.Fatalf("unexpected err: %s", err)
+		}
+	})
+	reflectiveAllocs := testing.AllocsPerRun(10, func() {
+		if _, err := reflective.Marshal((*post)(&p)); err != nil {
+			t.Fatalf("unexpected err: %s", err)
+		}
+	})
+	if allocs >= reflectiveAllocs {
+		t.Fatalf("generated marshal allocates %v times, reflective %v times", allocs, reflectiveAllocs)
+	}
+}
+
+func TestGeneratedConfigMismatch(t *testing.T) {
+	client := dynago.New(nil, &dynago.Config{DefaultOmitEmpty: true})
+	p := posts()[0]
+	want, err := client.Marshal((*post)(&p))
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	got, err := client.Marshal(&p)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, want, got)
+	var wantP, gotP gentest.Post
+	if err := client.Unmarshal(want, (*post)(&wantP)); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	if err := client.Unmarshal(want, &gotP); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, wantP, gotP)
+}
+
+func TestGeneratedPromoted(t *testing.T) {
+	// Meta is not generated, so the methods it gets from the embedded
+	// Table are not used.
+	got, err := reflective.Marshal(&gentest.Meta{ID: "m1"})
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, map[string]*dynamodb.AttributeValue{
+		"PK":     {S: aws.String("m1")},
+		"Values": {M: map[string]*dynamodb.AttributeValue{}},
+	}, got)
+}
+
+func BenchmarkMarshalGenerated(b *testing.B) {
+	client := dynago.New(nil)
+	c := gentest.Comment{PostID: "p1", Body: "Hi"}
+	for i := 0; i < b.N; i++ {
+		if _, err := client.Marshal(&c); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMarshalReflective(b *testing.B) {
+	c := gentest.Comment{PostID: "p1", Body: "Hi"}
+	for i := 0; i < b.N; i++ {
+		if _, err := reflective.Marshal((*comment)(&c)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
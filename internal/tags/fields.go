@@ -0,0 +1,94 @@
+package tags
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Visible returns the positions of the fields that are marshalled,
+// ordered by their index sequences. Fields of embedded structs are
+// promoted like encoding/json does: if several fields have the same
+// attribute name, the least nested one is used. If there are several
+// at that depth, the one with an attr tag naming it is used, or none
+// if there is not exactly one. Fields not marshalled, with the
+// attribute name "-", are kept.
+func Visible(fields []*Field) []int {
+	byName := make(map[string][]int)
+	for i, f := range fields {
+		if f.AttrName != "-" {
+			byName[f.AttrName] = append(byName[f.AttrName], i)
+		}
+	}
+	hidden := make(map[int]bool)
+	for _, is := range byName {
+		if len(is) == 1 {
+			continue
+		}
+		depth := len(fields[is[0]].Index)
+		for _, i := range is {
+			if len(fields[i].Index) < depth {
+				depth = len(fields[i].Index)
+			}
+		}
+		var shallowest, tagged []int
+		for _, i := range is {
+			if len(fields[i].Index) == depth {
+				shallowest = append(shallowest, i)
+				if fields[i].Tagged {
+					tagged = append(tagged, i)
+				}
+			}
+		}
+		dominant := -1
+		switch {
+		case len(shallowest) == 1:
+			dominant = shallowest[0]
+		case len(tagged) == 1:
+			dominant = tagged[0]
+		}
+		for _, i := range is {
+			if i != dominant {
+				hidden[i] = true
+			}
+		}
+	}
+	var visible []int
+	for i := range fields {
+		if !hidden[i] {
+			visible = append(visible, i)
+		}
+	}
+	sort.Slice(visible, func(i, j int) bool {
+		a, b := fields[visible[i]].Index, fields[visible[j]].Index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return visible
+}
+
+// lookup returns the position of the field with the given name, looked
+// up like reflect.Type.FieldByName does.
+func lookup(fields []*Field, name string, raw string) (int, error) {
+	found := -1
+	ambiguous := false
+	for i, f := range fields {
+		switch {
+		case f.Name != name:
+		case found < 0 || len(f.Index) < len(fields[found].Index):
+			found, ambiguous = i, false
+		case len(f.Index) == len(fields[found].Index):
+			ambiguous = true
+		}
+	}
+	if found < 0 || ambiguous {
+		return 0, fmt.Errorf("field %s referenced in fmt %q not found", name, raw)
+	}
+	if !fields[found].Exported {
+		return 0, fmt.Errorf("field %s referenced in fmt %q must be exported", name, raw)
+	}
+	return found, nil
+}
@@ -0,0 +1,226 @@
+// Package tags implements how dynago reads struct tags and fmt
+// templates. It is shared by the reflective codec of dynago and by
+// cmd/dynago-gen, so that generated code reads tags the same way.
+package tags
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Names are the names of the tags.
+type Names struct {
+	Attr    string
+	Fmt     string
+	Type    string
+	Layout  string
+	Copy    string
+	Version string
+	Auto    string
+	TTL     string
+}
+
+// DefaultNames are the default names of the tags.
+var DefaultNames = Names{
+	Attr:    "attr",
+	Fmt:     "fmt",
+	Type:    "type",
+	Layout:  "layout",
+	Copy:    "copy",
+	Version: "version",
+	Auto:    "auto",
+	TTL:     "ttl",
+}
+
+// Kind classifies types like reflect.Kind, with time.Time and byte
+// slices apart.
+type Kind int
+
+// Kinds of types.
+const (
+	KindOther Kind = iota
+	KindString
+	KindBool
+	KindInt
+	KindUint
+	KindFloat
+	KindTime
+	KindBytes
+	KindSlice
+	KindArray
+	KindMap
+	KindStruct
+	KindInterface
+)
+
+// Type describes the dereferenced type of a field.
+type Type struct {
+	Kind Kind
+
+	// String is the type as written in errors.
+	String string
+
+	// Text is true if values of the type are formatted in fmt
+	// templates with their encoding.TextMarshaler and
+	// encoding.TextUnmarshaler implementations.
+	Text bool
+
+	// SetMap is true if the type is a map with empty struct values,
+	// which is a set if its type tag is a set type.
+	SetMap bool
+
+	// TimeElem is true if the type is a slice of times or of pointers
+	// to times.
+	TimeElem bool
+}
+
+// Field is a struct field as described by its tags.
+type Field struct {
+	Name     string
+	Index    []int
+	Exported bool
+
+	// Tagged is true if the attr tag names the attribute.
+	Tagged bool
+
+	// AttrName is "-" if the field is not marshalled.
+	AttrName    string
+	AttrType    string
+	Fmt         string
+	Template    *Template
+	Layout      string
+	AttrsToCopy []string
+	OmitEmpty   bool
+	Null        bool
+	Version     bool
+	TTL         bool
+	Auto        string
+}
+
+// Parse reads the tags of the field with the given name and index
+// sequence, whose dereferenced type is ty. The attr tag options
+// default to omitEmpty and null.
+func Parse(name string, index []int, exported bool, tag reflect.StructTag, names Names, ty Type, omitEmpty bool, null bool) (*Field, error) {
+	f := Field{Name: name, Index: index, Exported: exported}
+	attrTag, hasAttr := tag.Lookup(names.Attr)
+	attrName, opts, _ := strings.Cut(attrTag, ",")
+	f.Tagged = attrName != ""
+	if !exported {
+		f.AttrName = "-"
+		return &f, nil
+	}
+	f.AttrName = name
+	f.OmitEmpty = omitEmpty
+	f.Null = null
+	if hasAttr {
+		if attrName != "" {
+			f.AttrName = attrName
+		}
+		for _, opt := range strings.Split(opts, ",") {
+			switch opt {
+			case "":
+			case "omitempty":
+				f.OmitEmpty = true
+			case "keepempty":
+				f.OmitEmpty = false
+			case "null":
+				f.Null = true
+			case "omitnull":
+				f.Null = false
+			default:
+				return nil, fmt.Errorf("unknown attr option %q of field %s", opt, name)
+			}
+		}
+	}
+	typeTag, typed := tag.Lookup(names.Type)
+	if typed {
+		f.AttrType = typeTag
+	} else {
+		switch ty.Kind {
+		case KindString, KindTime:
+			f.AttrType = "S"
+		case KindInt, KindUint, KindFloat:
+			f.AttrType = "N"
+		case KindBool:
+			f.AttrType = "BOOL"
+		case KindBytes:
+			f.AttrType = "B"
+		case KindSlice:
+			f.AttrType = "L"
+		case KindMap, KindStruct:
+			f.AttrType = "M"
+		}
+	}
+	if IsSetType(f.AttrType) && ty.Kind != KindSlice && ty.Kind != KindBytes && ty.Kind != KindArray && !ty.SetMap {
+		return nil, fmt.Errorf("field %s of type %s must be a slice or a map[T]struct{}", name, f.AttrType)
+	}
+	if tag, ok := tag.Lookup(names.Fmt); ok {
+		f.Fmt = tag
+		f.AttrType = "S"
+	} else {
+		f.Fmt = "{}"
+	}
+	tmpl, err := ParseTemplate(f.Fmt)
+	if err != nil {
+		return nil, err
+	}
+	f.Template = tmpl
+	layoutTag, hasLayout := tag.Lookup(names.Layout)
+	switch {
+	case hasLayout:
+		f.Layout = layoutTag
+	case ty.Kind == KindTime, ty.Kind == KindSlice && ty.TimeElem:
+		f.Layout = time.RFC3339
+	}
+	if ty.Kind == KindTime {
+		// Times with a Unix epoch layout are stored as numbers, and
+		// times of type N default to seconds.
+		switch {
+		case f.AttrType == "N" && !IsUnixLayout(f.Layout):
+			if hasLayout {
+				return nil, fmt.Errorf("layout of time field %s of type N must be %s, %s or %s", name, LayoutUnix, LayoutUnixMilli, LayoutUnixNano)
+			}
+			f.Layout = LayoutUnix
+		case !typed && f.Fmt == "{}" && IsUnixLayout(f.Layout):
+			f.AttrType = "N"
+		}
+	}
+	if tag, ok := tag.Lookup(names.Copy); ok {
+		f.AttrsToCopy = strings.Split(tag, ",")
+	}
+	if _, ok := tag.Lookup(names.Version); ok {
+		if ty.Kind != KindInt && ty.Kind != KindUint {
+			return nil, fmt.Errorf("version field %s must be an integer", name)
+		}
+		f.Version = true
+	}
+	if _, ok := tag.Lookup(names.TTL); ok {
+		if ty.Kind != KindTime {
+			return nil, fmt.Errorf("ttl field %s must be a time.Time", name)
+		}
+		f.TTL = true
+		f.AttrType = "N"
+		f.Layout = LayoutUnix
+	}
+	if tag, ok := tag.Lookup(names.Auto); ok {
+		if ty.Kind != KindTime {
+			return nil, fmt.Errorf("auto field %s must be a time.Time", name)
+		}
+		if tag != "create" && tag != "update" {
+			return nil, fmt.Errorf("auto field %s must be create or update", name)
+		}
+		f.Auto = tag
+	}
+	return &f, nil
+}
+
+// IsSetType reports whether the attribute type is a set type.
+func IsSetType(attrType string) bool {
+	switch attrType {
+	case "SS", "NS", "BS":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package tags_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/twharmon/dynago/internal/tags"
+)
+
+func TestParseTime(t *testing.T) {
+	f, err := tags.Parse("Created", []int{0}, true, `type:"N"`, tags.DefaultNames, tags.Type{Kind: tags.KindTime}, false, false)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	if f.AttrType != "N" || f.Layout != tags.LayoutUnix {
+		t.Fatalf("unexpected type %s and layout %s", f.AttrType, f.Layout)
+	}
+	f, err = tags.Parse("Created", []int{0}, true, `layout:"unixmilli"`, tags.DefaultNames, tags.Type{Kind: tags.KindTime}, false, false)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	if f.AttrType != "N" || f.Layout != tags.LayoutUnixMilli {
+		t.Fatalf("unexpected type %s and layout %s", f.AttrType, f.Layout)
+	}
+	f, err = tags.Parse("Created", []int{0}, true, "", tags.DefaultNames, tags.Type{Kind: tags.KindTime}, false, false)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	if f.AttrType != "S" || f.Layout != time.RFC3339 {
+		t.Fatalf("unexpected type %s and layout %s", f.AttrType, f.Layout)
+	}
+}
+
+func TestParseErr(t *testing.T) {
+	for _, tc := range []struct {
+		tag reflect.StructTag
+		ty  tags.Type
+	}{
+		{`attr:",upper"`, tags.Type{Kind: tags.KindString}},
+		{`type:"SS"`, tags.Type{Kind: tags.KindString}},
+		{`type:"N" layout:"2006"`, tags.Type{Kind: tags.KindTime}},
+		{`version:""`, tags.Type{Kind: tags.KindFloat}},
+		{`ttl:""`, tags.Type{Kind: tags.KindInt}},
+		{`auto:"delete"`, tags.Type{Kind: tags.KindTime}},
+		{`fmt:"{"`, tags.Type{Kind: tags.KindString}},
+	} {
+		if _, err := tags.Parse("Field", []int{0}, true, tc.tag, tags.DefaultNames, tc.ty, false, false); err == nil {
+			t.Fatalf("expected err for %s", tc.tag)
+		}
+	}
+}
+
+func TestVisible(t *testing.T) {
+	fields := []*tags.Field{
+		{Name: "ID", Index: []int{1}, AttrName: "ID"},
+		{Name: "ID", Index: []int{0, 0}, AttrName: "ID"},
+		{Name: "A", Index: []int{0, 1}, AttrName: "Name", Tagged: true},
+		{Name: "B", Index: []int{2, 0}, AttrName: "Name"},
+		{Name: "C", Index: []int{3, 0}, AttrName: "Dup"},
+		{Name: "D", Index: []int{4, 0}, AttrName: "Dup"},
+	}
+	if want, got := []int{2, 0}, tags.Visible(fields); !reflect.DeepEqual(want, got) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
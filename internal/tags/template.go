@@ -0,0 +1,249 @@
+package tags
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var placeholderRegExp = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)?(?::(0)?([1-9][0-9]*)?([ds])?)?$`)
+
+// Template is a parsed fmt tag. A placeholder is written as {} for the
+// field itself, or as {Name} for another field of the struct. It may
+// have a spec after a colon, such as {:010d}, that pads the value to a
+// width with spaces, or with zeros if the width starts with 0, which
+// is only allowed for numbers. The verb d requires an integer value,
+// and s any value. Literal braces are written as {{ and }}.
+//
+// Values followed by a literal are escaped, so that they may contain
+// it: backslashes and the first character of the literal are prefixed
+// with a backslash. The last value of a template is not escaped.
+type Template struct {
+	Raw   string
+	Parts []*Part
+
+	literals     []string
+	placeholders []bool
+}
+
+// Part is a literal or a placeholder of a template.
+type Part struct {
+	Literal     string
+	Placeholder bool
+	Name        string
+	Zero        bool
+	Width       int
+	Verb        byte
+}
+
+// ParseTemplate parses a fmt tag.
+func ParseTemplate(s string) (*Template, error) {
+	t := Template{Raw: s}
+	var lit strings.Builder
+	flush := func() {
+		if lit.Len() > 0 {
+			t.Parts = append(t.Parts, &Part{Literal: lit.String()})
+			lit.Reset()
+		}
+	}
+	for i := 0; i < len(s); i++ {
+		switch {
+		case strings.HasPrefix(s[i:], "{{"):
+			lit.WriteByte('{')
+			i++
+		case strings.HasPrefix(s[i:], "}}"):
+			lit.WriteByte('}')
+			i++
+		case s[i] == '}':
+			return nil, fmt.Errorf("unexpected } in fmt %q", s)
+		case s[i] == '{':
+			end := strings.IndexByte(s[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed { in fmt %q", s)
+			}
+			m := placeholderRegExp.FindStringSubmatch(s[i+1 : i+end])
+			if m == nil {
+				return nil, fmt.Errorf("invalid placeholder %s in fmt %q", s[i:i+end+1], s)
+			}
+			flush()
+			if n := len(t.Parts); n > 0 && t.Parts[n-1].Placeholder {
+				return nil, fmt.Errorf("placeholders must be separated by a literal in fmt %q", s)
+			}
+			p := Part{Placeholder: true, Name: m[1], Zero: m[2] != ""}
+			if m[3] != "" {
+				p.Width, _ = strconv.Atoi(m[3])
+			}
+			if m[4] != "" {
+				p.Verb = m[4][0]
+			}
+			if p.Zero && p.Width == 0 {
+				return nil, fmt.Errorf("placeholder %s must have a width in fmt %q", s[i:i+end+1], s)
+			}
+			t.Parts = append(t.Parts, &p)
+			i += end
+		default:
+			lit.WriteByte(s[i])
+		}
+	}
+	flush()
+	for _, p := range t.Parts {
+		t.literals = append(t.literals, p.Literal)
+		t.placeholders = append(t.placeholders, p.Placeholder)
+	}
+	return &t, nil
+}
+
+// Resolve returns the positions in fields of the fields formatted by
+// the parts of the template of fields[self], or -1 for literals, and
+// checks that they can be formatted. typeOf returns the type of the
+// field at a position.
+func (t *Template) Resolve(self int, fields []*Field, typeOf func(int) Type) ([]int, error) {
+	resolved := make([]int, len(t.Parts))
+	for i, p := range t.Parts {
+		resolved[i] = -1
+		if !p.Placeholder {
+			continue
+		}
+		j := self
+		if p.Name != "" {
+			var err error
+			if j, err = lookup(fields, p.Name, t.Raw); err != nil {
+				return nil, err
+			}
+		}
+		f, ty := fields[j], typeOf(j)
+		unix := ty.Kind == KindTime && IsUnixLayout(f.Layout)
+		switch ty.Kind {
+		case KindTime, KindString, KindBool, KindInt, KindUint, KindFloat:
+		default:
+			if !ty.Text {
+				return nil, fmt.Errorf("field %s of type %s can not be formatted in fmt %q", f.Name, ty.String, t.Raw)
+			}
+		}
+		if p.Verb == 'd' && ty.Kind != KindInt && ty.Kind != KindUint && !unix {
+			return nil, fmt.Errorf("field %s of type %s can not be formatted with d in fmt %q", f.Name, ty.String, t.Raw)
+		}
+		if p.Zero && (ty.Text || ty.Kind != KindInt && ty.Kind != KindUint && ty.Kind != KindFloat && !unix) {
+			return nil, fmt.Errorf("field %s of type %s can not be padded with zeros in fmt %q", f.Name, ty.String, t.Raw)
+		}
+		resolved[i] = j
+	}
+	return resolved, nil
+}
+
+// Value returns the formatted value s of the placeholder at position
+// i, padded to its width and escaped.
+func (t *Template) Value(i int, s string) string {
+	p := t.Parts[i]
+	s = Pad(s, p.Width, p.Zero)
+	if i+1 < len(t.Parts) {
+		s = Escape(s, t.Parts[i+1].Literal)
+	}
+	return s
+}
+
+// Scan splits s into the unescaped values of the parts of the
+// template. False is returned if s does not match it.
+func (t *Template) Scan(s string) ([]string, bool) {
+	return Scan(s, t.literals, t.placeholders)
+}
+
+// Trim removes the padding of the value s of the placeholder.
+func (p *Part) Trim(s string) string {
+	if p.Width > 0 && !p.Zero {
+		return strings.TrimLeft(s, " ")
+	}
+	return s
+}
+
+// Scan splits s into the unescaped values of the parts of a template,
+// given by their literals and whether they are placeholders. False is
+// returned if s does not match it.
+func Scan(s string, literals []string, placeholders []bool) ([]string, bool) {
+	values := make([]string, len(literals))
+	pos := 0
+	for i, lit := range literals {
+		if !placeholders[i] {
+			if !strings.HasPrefix(s[pos:], lit) {
+				return nil, false
+			}
+			pos += len(lit)
+			continue
+		}
+		if i+1 == len(literals) {
+			values[i] = s[pos:]
+			pos = len(s)
+			continue
+		}
+		end := IndexUnescaped(s[pos:], literals[i+1])
+		if end < 0 {
+			return nil, false
+		}
+		values[i] = Unescape(s[pos : pos+end])
+		pos += end
+	}
+	return values, pos == len(s)
+}
+
+// Pad pads s to the width with spaces, or with zeros after the sign
+// if zero is true.
+func Pad(s string, width int, zero bool) string {
+	if len(s) >= width {
+		return s
+	}
+	if !zero {
+		return strings.Repeat(" ", width-len(s)) + s
+	}
+	pad := strings.Repeat("0", width-len(s))
+	if strings.HasPrefix(s, "-") {
+		return "-" + pad + s[1:]
+	}
+	return pad + s
+}
+
+// Escape escapes backslashes and the first character of the literal
+// following a value.
+func Escape(s string, next string) string {
+	if next == "" || !strings.ContainsAny(s, `\`+next[:1]) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' || s[i] == next[0] {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// Unescape removes the backslashes added by Escape.
+func Unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// IndexUnescaped returns the index of the first occurrence of lit in s
+// that is not escaped, or -1 if there is none.
+func IndexUnescaped(s string, lit string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(s[i:], lit) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,37 @@
+package tags_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/twharmon/dynago/internal/tags"
+)
+
+func TestParseTemplateErr(t *testing.T) {
+	for _, s := range []string{"{", "}", "{:0}", "{}{Name}", "{:x}"} {
+		if _, err := tags.ParseTemplate(s); err == nil {
+			t.Fatalf("expected err for %q", s)
+		}
+	}
+}
+
+func TestTemplateValueScan(t *testing.T) {
+	tmpl, err := tags.ParseTemplate("Org#{OrgID}#Seq#{Seq:05d}")
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	s := "Org#" + tmpl.Value(1, `a#b\c`) + "#Seq#" + tmpl.Value(3, "-42")
+	if s != `Org#a\#b\\c#Seq#-0042` {
+		t.Fatalf("unexpected %q", s)
+	}
+	values, ok := tmpl.Scan(s)
+	if !ok {
+		t.Fatalf("expected %q to match", s)
+	}
+	if want := []string{"", `a#b\c`, "", "-0042"}; !reflect.DeepEqual(want, values) {
+		t.Fatalf("expected %q, got %q", want, values)
+	}
+	if _, ok := tmpl.Scan("Team#a"); ok {
+		t.Fatalf("expected no match")
+	}
+}
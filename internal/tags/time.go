@@ -0,0 +1,57 @@
+package tags
+
+import (
+	"strconv"
+	"time"
+)
+
+// Layouts of times stored as the number of seconds, milliseconds or
+// nanoseconds since the Unix epoch.
+const (
+	LayoutUnix      = "unix"
+	LayoutUnixMilli = "unixmilli"
+	LayoutUnixNano  = "unixnano"
+)
+
+// IsUnixLayout reports whether the layout is one of the Unix epoch
+// layouts.
+func IsUnixLayout(layout string) bool {
+	switch layout {
+	case LayoutUnix, LayoutUnixMilli, LayoutUnixNano:
+		return true
+	}
+	return false
+}
+
+// FormatTime formats t with the layout, which may be one of the Unix
+// epoch layouts.
+func FormatTime(t time.Time, layout string) string {
+	switch layout {
+	case LayoutUnix:
+		return strconv.FormatInt(t.Unix(), 10)
+	case LayoutUnixMilli:
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	case LayoutUnixNano:
+		return strconv.FormatInt(t.UnixNano(), 10)
+	}
+	return t.Format(layout)
+}
+
+// ParseTime parses s with the layout, which may be one of the Unix
+// epoch layouts. Times parsed with those are in UTC.
+func ParseTime(s string, layout string) (time.Time, error) {
+	if !IsUnixLayout(layout) {
+		return time.Parse(layout, s)
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	switch layout {
+	case LayoutUnixMilli:
+		return time.UnixMilli(n).UTC(), nil
+	case LayoutUnixNano:
+		return time.Unix(0, n).UTC(), nil
+	}
+	return time.Unix(n, 0).UTC(), nil
+}
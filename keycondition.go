@@ -0,0 +1,103 @@
+package dynago
+
+import (
+	"fmt"
+	"strings"
+)
+
+// KeyName is the name of a key attribute used to build a
+// KeyCondition.
+type KeyName string
+
+// KeyCondition represents the KeyConditionExpression of a Query
+// operation. Values are marshalled as the attribute with the same name
+// of the queried struct, so its fmt tag is applied to them.
+type KeyCondition struct {
+	name   KeyName
+	op     string
+	values []interface{}
+	and    *KeyCondition
+}
+
+// Key returns a KeyName for the attribute with the given name.
+func Key(name string) KeyName {
+	return KeyName(name)
+}
+
+// Equal returns a condition that the key is equal to v.
+func (k KeyName) Equal(v interface{}) *KeyCondition {
+	return &KeyCondition{name: k, op: "=", values: []interface{}{v}}
+}
+
+// LessThan returns a condition that the key is less than v.
+func (k KeyName) LessThan(v interface{}) *KeyCondition {
+	return &KeyCondition{name: k, op: "<", values: []interface{}{v}}
+}
+
+// LessThanEqual returns a condition that the key is less than or
+// equal to v.
+func (k KeyName) LessThanEqual(v interface{}) *KeyCondition {
+	return &KeyCondition{name: k, op: "<=", values: []interface{}{v}}
+}
+
+// GreaterThan returns a condition that the key is greater than v.
+func (k KeyName) GreaterThan(v interface{}) *KeyCondition {
+	return &KeyCondition{name: k, op: ">", values: []interface{}{v}}
+}
+
+// GreaterThanEqual returns a condition that the key is greater than
+// or equal to v.
+func (k KeyName) GreaterThanEqual(v interface{}) *KeyCondition {
+	return &KeyCondition{name: k, op: ">=", values: []interface{}{v}}
+}
+
+// Between returns a condition that the key is greater than or equal
+// to lower and less than or equal to upper.
+func (k KeyName) Between(lower interface{}, upper interface{}) *KeyCondition {
+	return &KeyCondition{name: k, op: "BETWEEN", values: []interface{}{lower, upper}}
+}
+
+// BeginsWith returns a condition that the key begins with prefix. The
+// fmt template of the attribute is cut after the placeholder prefix
+// is substituted in, so with `fmt:"Post#{}"`, BeginsWith("2023")
+// matches keys beginning with "Post#2023".
+func (k KeyName) BeginsWith(prefix interface{}) *KeyCondition {
+	return &KeyCondition{name: k, op: "begins_with", values: []interface{}{prefix}}
+}
+
+// And returns a condition that both c and other are true. It is
+// usually used to combine a partition key condition with a sort key
+// condition.
+func (c *KeyCondition) And(other *KeyCondition) *KeyCondition {
+	cp := *c
+	if cp.and != nil {
+		cp.and = cp.and.And(other)
+	} else {
+		cp.and = other
+	}
+	return &cp
+}
+
+func (c *KeyCondition) build(e *expression) (string, error) {
+	var conds []string
+	for ; c != nil; c = c.and {
+		name := e.name(string(c.name))
+		vals := make([]string, len(c.values))
+		for i, v := range c.values {
+			ph, err := e.value(string(c.name), v, c.op == "begins_with")
+			if err != nil {
+				return "", fmt.Errorf("key %s: %w", c.name, err)
+			}
+			vals[i] = ph
+		}
+		switch c.op {
+		case "BETWEEN":
+			conds = append(conds, fmt.Sprintf("%s BETWEEN %s AND %s", name, vals[0], vals[1]))
+		case "begins_with":
+			conds = append(conds, fmt.Sprintf("begins_with(%s, %s)", name, vals[0]))
+		default:
+			conds = append(conds, fmt.Sprintf("%s %s %s", name, c.op, vals[0]))
+		}
+	}
+	return strings.Join(conds, " AND "), nil
+}
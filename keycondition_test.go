@@ -0,0 +1,176 @@
+package dynago_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/twharmon/dynago"
+)
+
+func TestKeyConditionBasic(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Post struct {
+		Author string `attr:"PK" fmt:"Author#{}"`
+		Slug   string `attr:"SK" fmt:"Post#{}"`
+	}
+	want := []Post{{Author: "bar", Slug: "hello"}}
+	ddb.MockQuery(&dynamodb.QueryInput{
+		TableName:              aws.String("foo"),
+		ConsistentRead:         aws.Bool(false),
+		KeyConditionExpression: aws.String("#k0 = :k0 AND begins_with(#k1, :k1)"),
+		ExpressionAttributeNames: map[string]*string{
+			"#k0": aws.String("PK"),
+			"#k1": aws.String("SK"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":k0": {S: aws.String("Author#bar")},
+			":k1": {S: aws.String("Post#he")},
+		},
+	}, &dynamodb.QueryOutput{
+		Items: []map[string]*dynamodb.AttributeValue{{
+			"PK": {S: aws.String("Author#bar")},
+			"SK": {S: aws.String("Post#hello")},
+		}},
+	})
+	var got []Post
+	if err := client.Query(&got).
+		KeyCondition(dynago.Key("PK").Equal("bar").And(dynago.Key("SK").BeginsWith("he"))).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, want, got)
+	ddb.done()
+}
+
+func TestKeyConditionBetween(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Event struct {
+		Org     string    `attr:"PK" fmt:"Org#{}"`
+		Created time.Time `attr:"SK" fmt:"Created#{}" layout:"2006-01-02"`
+		Count   int64     `attr:"GSISK"`
+	}
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)
+	ddb.MockQuery(&dynamodb.QueryInput{
+		TableName:              aws.String("foo"),
+		ConsistentRead:         aws.Bool(false),
+		KeyConditionExpression: aws.String("#k0 = :k0 AND #k1 BETWEEN :k1 AND :k2"),
+		ExpressionAttributeNames: map[string]*string{
+			"#k0": aws.String("PK"),
+			"#k1": aws.String("SK"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":k0": {S: aws.String("Org#bar")},
+			":k1": {S: aws.String("Created#2023-01-01")},
+			":k2": {S: aws.String("Created#2023-02-01")},
+		},
+	}, &dynamodb.QueryOutput{})
+	var got []*Event
+	if err := client.Query(&got).
+		KeyCondition(dynago.Key("PK").Equal("bar").And(dynago.Key("SK").Between(start, end))).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	ddb.done()
+}
+
+func TestKeyConditionIndex(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Event struct {
+		Org   string `attr:"PK" fmt:"Org#{}"`
+		Count int64  `attr:"GSISK"`
+	}
+	ddb.MockQuery(&dynamodb.QueryInput{
+		TableName:              aws.String("foo"),
+		IndexName:              aws.String("GSI"),
+		ConsistentRead:         aws.Bool(false),
+		KeyConditionExpression: aws.String("#k0 = :k0 AND #k1 >= :k1"),
+		ExpressionAttributeNames: map[string]*string{
+			"#k0": aws.String("GSIPK"),
+			"#k1": aws.String("GSISK"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":k0": {S: aws.String("Event")},
+			":k1": {N: aws.String("3")},
+		},
+	}, &dynamodb.QueryOutput{})
+	var got []*Event
+	if err := client.Query(&got).
+		IndexName("GSI").
+		KeyCondition(dynago.Key("GSIPK").Equal("Event").And(dynago.Key("GSISK").GreaterThanEqual(3))).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	ddb.done()
+}
+
+func TestKeyConditionCompoundFmt(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Event struct {
+		Org     string `attr:"PK" fmt:"Org#{}"`
+		Country string `attr:"SK" fmt:"Country#{}#City#{City}"`
+		City    string `attr:"-"`
+	}
+	ddb.MockQuery(&dynamodb.QueryInput{
+		TableName:              aws.String("foo"),
+		ConsistentRead:         aws.Bool(false),
+		KeyConditionExpression: aws.String("#k0 = :k0 AND begins_with(#k1, :k1)"),
+		ExpressionAttributeNames: map[string]*string{
+			"#k0": aws.String("PK"),
+			"#k1": aws.String("SK"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":k0": {S: aws.String("Org#bar")},
+			":k1": {S: aws.String("Country#US#City#")},
+		},
+	}, &dynamodb.QueryOutput{})
+	var got []*Event
+	if err := client.Query(&got).
+		KeyCondition(dynago.Key("PK").Equal("bar").And(dynago.Key("SK").BeginsWith("US"))).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	ddb.done()
+
+	ddb.MockQuery(&dynamodb.QueryInput{
+		TableName:              aws.String("foo"),
+		ConsistentRead:         aws.Bool(false),
+		KeyConditionExpression: aws.String("#k0 = :k0 AND #k1 = :k1"),
+		ExpressionAttributeNames: map[string]*string{
+			"#k0": aws.String("PK"),
+			"#k1": aws.String("SK"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":k0": {S: aws.String("Org#bar")},
+			":k1": {S: aws.String("Country#US#City#Boston")},
+		},
+	}, &dynamodb.QueryOutput{})
+	if err := client.Query(&got).
+		KeyCondition(dynago.Key("PK").Equal("bar").And(dynago.Key("SK").Equal(Event{Country: "US", City: "Boston"}))).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	ddb.done()
+}
+
+func TestKeyConditionCompoundFmtErr(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Event struct {
+		Country string `attr:"SK" fmt:"Country#{}#City#{City}"`
+		City    string `attr:"-"`
+	}
+	var got []*Event
+	err := client.Query(&got).KeyCondition(dynago.Key("SK").Equal("US")).Exec()
+	if err == nil || !strings.Contains(err.Error(), "Country#{}#City#{City}") {
+		t.Fatalf("want fmt error; got %v", err)
+	}
+	ddb.done()
+}
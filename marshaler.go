@@ -0,0 +1,214 @@
+package dynago
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// AttributeMarshaler is implemented by types that marshal themselves
+// into a DynamoDB attribute value. Types that do not implement it but
+// implement encoding.TextMarshaler are marshalled as a String.
+type AttributeMarshaler interface {
+	MarshalAttribute() (*dynamodb.AttributeValue, error)
+}
+
+// AttributeUnmarshaler is implemented by types that unmarshal
+// themselves from a DynamoDB attribute value. Types that do not
+// implement it but implement encoding.TextUnmarshaler are unmarshalled
+// from a String.
+type AttributeUnmarshaler interface {
+	UnmarshalAttribute(*dynamodb.AttributeValue) error
+}
+
+// ItemMarshaler is implemented by structs that convert themselves into
+// a DynamoDB item, such as the MarshalDynago methods generated by
+// cmd/dynago-gen. Marshal uses it instead of reflection, unless the
+// struct was generated for a different GeneratedConfig.
+type ItemMarshaler interface {
+	MarshalDynago() (map[string]*dynamodb.AttributeValue, error)
+}
+
+// ItemUnmarshaler is implemented by structs that convert a DynamoDB
+// item into themselves, such as the UnmarshalDynago methods generated
+// by cmd/dynago-gen. Unmarshal uses it instead of reflection, unless
+// the struct was generated for a different GeneratedConfig.
+type ItemUnmarshaler interface {
+	UnmarshalDynago(map[string]*dynamodb.AttributeValue) error
+}
+
+// GeneratedConfig is the part of a Config that the methods generated
+// by cmd/dynago-gen depend on.
+type GeneratedConfig struct {
+	// Type is the struct type the methods were generated for, so that
+	// methods promoted from an embedded struct are not used.
+	Type reflect.Type
+
+	AttrTagName        string
+	FmtTagName         string
+	TypeTagName        string
+	LayoutTagName      string
+	AttrsToCopyTagName string
+	VersionTagName     string
+	AutoTagName        string
+	TTLTagName         string
+	DefaultOmitEmpty   bool
+	NullPointers       bool
+}
+
+// generated is implemented by pointers to structs with methods
+// generated by cmd/dynago-gen. DynagoConfig returns the configuration
+// they were generated for.
+type generated interface {
+	DynagoConfig() GeneratedConfig
+}
+
+// generatedConfigs caches the results of generatedConfig by type.
+var generatedConfigs sync.Map
+
+// generatedConfig returns the configuration the methods of the struct
+// type ty were generated for, or nil if *ty has no DynagoConfig
+// method.
+func generatedConfig(ty reflect.Type) *GeneratedConfig {
+	if c, ok := generatedConfigs.Load(ty); ok {
+		return c.(*GeneratedConfig)
+	}
+	var c *GeneratedConfig
+	if g, ok := reflect.New(ty).Interface().(generated); ok {
+		c = callDynagoConfig(g)
+	}
+	generatedConfigs.Store(ty, c)
+	return c
+}
+
+// callDynagoConfig calls the DynagoConfig method of g. A method
+// promoted from a nil embedded pointer panics, in which case a config
+// matching no client is returned.
+func callDynagoConfig(g generated) (c *GeneratedConfig) {
+	defer func() {
+		if recover() != nil {
+			c = &GeneratedConfig{}
+		}
+	}()
+	config := g.DynagoConfig()
+	return &config
+}
+
+// useGenerated reports whether the ItemMarshaler or ItemUnmarshaler v
+// is used by the client. Generated methods are only used if they were
+// generated for the type of v, and not promoted from an embedded
+// struct, and for the configuration of the client.
+func (d *Dynago) useGenerated(v interface{}) bool {
+	ty := derefType(reflect.TypeOf(v))
+	g := generatedConfig(ty)
+	if g == nil {
+		return true
+	}
+	c := d.config
+	return *g == GeneratedConfig{
+		Type:               ty,
+		AttrTagName:        c.AttrTagName,
+		FmtTagName:         c.FmtTagName,
+		TypeTagName:        c.TypeTagName,
+		LayoutTagName:      c.LayoutTagName,
+		AttrsToCopyTagName: c.AttrsToCopyTagName,
+		VersionTagName:     c.VersionTagName,
+		AutoTagName:        c.AutoTagName,
+		TTLTagName:         c.TTLTagName,
+		DefaultOmitEmpty:   c.DefaultOmitEmpty,
+		NullPointers:       c.NullPointers,
+	}
+}
+
+var (
+	attributeMarshalerType   = reflect.TypeOf((*AttributeMarshaler)(nil)).Elem()
+	attributeUnmarshalerType = reflect.TypeOf((*AttributeUnmarshaler)(nil)).Elem()
+	textMarshalerType        = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType      = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// customTypes caches the results of isCustom by type.
+var customTypes sync.Map
+
+// isCustom reports whether values of the type ty, or of the type it
+// points to, marshal or unmarshal themselves. time.Time is formatted
+// with its layout instead.
+func isCustom(ty reflect.Type) bool {
+	if custom, ok := customTypes.Load(ty); ok {
+		return custom.(bool)
+	}
+	custom := implementsCustom(derefType(ty))
+	customTypes.Store(ty, custom)
+	return custom
+}
+
+func implementsCustom(ty reflect.Type) bool {
+	if ty == timeType {
+		return false
+	}
+	for _, iface := range []reflect.Type{attributeMarshalerType, attributeUnmarshalerType, textMarshalerType, textUnmarshalerType} {
+		if ty.Implements(iface) || reflect.PointerTo(ty).Implements(iface) {
+			return true
+		}
+	}
+	return false
+}
+
+// marshalCustom marshals v with its AttributeMarshaler or
+// encoding.TextMarshaler implementation. False is returned if it has
+// neither.
+func marshalCustom(v reflect.Value) (*dynamodb.AttributeValue, bool, error) {
+	if !v.IsValid() || !isCustom(v.Type()) || (v.Kind() == reflect.Pointer && v.IsNil()) {
+		return nil, false, nil
+	}
+	if !v.Type().Implements(attributeMarshalerType) && !v.Type().Implements(textMarshalerType) && v.CanAddr() {
+		v = v.Addr()
+	}
+	if !v.CanInterface() {
+		return nil, false, nil
+	}
+	switch m := v.Interface().(type) {
+	case AttributeMarshaler:
+		av, err := m.MarshalAttribute()
+		if err != nil {
+			return nil, true, fmt.Errorf("MarshalAttribute: %w", err)
+		}
+		return av, true, nil
+	case encoding.TextMarshaler:
+		b, err := m.MarshalText()
+		if err != nil {
+			return nil, true, fmt.Errorf("MarshalText: %w", err)
+		}
+		s := string(b)
+		return &dynamodb.AttributeValue{S: &s}, true, nil
+	}
+	return nil, false, nil
+}
+
+// unmarshalCustom unmarshals av into v with its AttributeUnmarshaler
+// or encoding.TextUnmarshaler implementation. False is returned if it
+// has neither.
+func unmarshalCustom(v reflect.Value, av *dynamodb.AttributeValue) (bool, error) {
+	if v.Kind() == reflect.Pointer || !isCustom(v.Type()) || !v.CanAddr() {
+		return false, nil
+	}
+	switch u := v.Addr().Interface().(type) {
+	case AttributeUnmarshaler:
+		if err := u.UnmarshalAttribute(av); err != nil {
+			return true, fmt.Errorf("UnmarshalAttribute: %w", err)
+		}
+		return true, nil
+	case encoding.TextUnmarshaler:
+		if av.S == nil {
+			return true, nil
+		}
+		if err := u.UnmarshalText([]byte(*av.S)); err != nil {
+			return true, fmt.Errorf("UnmarshalText: %w", err)
+		}
+		return true, nil
+	}
+	return false, nil
+}
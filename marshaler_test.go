@@ -0,0 +1,184 @@
+package dynago_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/twharmon/dynago"
+)
+
+type Money struct {
+	Cents int64
+}
+
+func (m Money) MarshalAttribute() (*dynamodb.AttributeValue, error) {
+	return &dynamodb.AttributeValue{N: aws.String(fmt.Sprintf("%d.%02d", m.Cents/100, m.Cents%100))}, nil
+}
+
+func (m *Money) UnmarshalAttribute(av *dynamodb.AttributeValue) error {
+	if av.N == nil {
+		return errors.New("money must be a number")
+	}
+	dollars, cents, _ := strings.Cut(*av.N, ".")
+	d, err := strconv.ParseInt(dollars, 10, 64)
+	if err != nil {
+		return err
+	}
+	c, err := strconv.ParseInt(cents, 10, 64)
+	if err != nil {
+		return err
+	}
+	m.Cents = d*100 + c
+	return nil
+}
+
+type UUID [4]byte
+
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(hex.EncodeToString(u[:])), nil
+}
+
+func (u *UUID) UnmarshalText(b []byte) error {
+	_, err := hex.Decode(u[:], b)
+	return err
+}
+
+type Status int
+
+func (s Status) MarshalText() ([]byte, error) {
+	switch s {
+	case 1:
+		return []byte("active"), nil
+	case 2:
+		return []byte("closed"), nil
+	}
+	return nil, fmt.Errorf("invalid status %d", s)
+}
+
+func (s *Status) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case "active":
+		*s = 1
+	case "closed":
+		*s = 2
+	default:
+		return fmt.Errorf("invalid status %s", b)
+	}
+	return nil
+}
+
+type LineItem struct {
+	Price Money
+}
+
+type Order struct {
+	*SimpleTable
+	ID     UUID   `attr:"PK" fmt:"Order#{}"`
+	Status Status `attr:"SK" fmt:"Status#{}"`
+	Total  Money
+	Refund *Money
+	Prices []Money
+	Item   LineItem
+	Owner  UUID
+}
+
+func TestMarshalCustom(t *testing.T) {
+	client := dynago.New(nil)
+	o := Order{
+		ID:     UUID{1, 2, 3, 4},
+		Status: 1,
+		Total:  Money{Cents: 1050},
+		Prices: []Money{{Cents: 1}, {Cents: 200}},
+		Item:   LineItem{Price: Money{Cents: 99}},
+		Owner:  UUID{0xff},
+	}
+	want := map[string]*dynamodb.AttributeValue{
+		"PK":    {S: aws.String("Order#01020304")},
+		"SK":    {S: aws.String("Status#active")},
+		"Total": {N: aws.String("10.50")},
+		"Prices": {L: []*dynamodb.AttributeValue{
+			{N: aws.String("0.01")},
+			{N: aws.String("2.00")},
+		}},
+		"Item": {M: map[string]*dynamodb.AttributeValue{
+			"Price": {N: aws.String("0.99")},
+		}},
+		"Owner": {S: aws.String("ff000000")},
+	}
+	got, err := client.Marshal(&o)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, want, got)
+}
+
+func TestUnmarshalCustom(t *testing.T) {
+	client := dynago.New(nil)
+	item := map[string]*dynamodb.AttributeValue{
+		"PK":     {S: aws.String("Order#01020304")},
+		"SK":     {S: aws.String("Status#closed")},
+		"Total":  {N: aws.String("10.50")},
+		"Refund": {N: aws.String("1.00")},
+		"Prices": {L: []*dynamodb.AttributeValue{
+			{N: aws.String("0.01")},
+			{N: aws.String("2.00")},
+		}},
+		"Item": {M: map[string]*dynamodb.AttributeValue{
+			"Price": {N: aws.String("0.99")},
+		}},
+		"Owner": {S: aws.String("ff000000")},
+	}
+	want := Order{
+		ID:     UUID{1, 2, 3, 4},
+		Status: 2,
+		Total:  Money{Cents: 1050},
+		Refund: &Money{Cents: 100},
+		Prices: []Money{{Cents: 1}, {Cents: 200}},
+		Item:   LineItem{Price: Money{Cents: 99}},
+		Owner:  UUID{0xff},
+	}
+	var got Order
+	if err := client.Unmarshal(item, &got); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, want, got)
+}
+
+func TestMarshalCustomErr(t *testing.T) {
+	client := dynago.New(nil)
+	type Account struct {
+		Status Status
+	}
+	if _, err := client.Marshal(&Account{Status: 3}); err == nil {
+		t.Fatalf("expected err")
+	}
+}
+
+func TestUnmarshalCustomErr(t *testing.T) {
+	client := dynago.New(nil)
+	type Account struct {
+		Balance Money
+	}
+	var got Account
+	if err := client.Unmarshal(map[string]*dynamodb.AttributeValue{
+		"Balance": {S: aws.String("foo")},
+	}, &got); err == nil {
+		t.Fatalf("expected err")
+	}
+}
+
+func TestMarshalUnknownKind(t *testing.T) {
+	client := dynago.New(nil)
+	type Account struct {
+		Updates chan int
+	}
+	if _, err := client.Marshal(&Account{Updates: make(chan int)}); err == nil {
+		t.Fatalf("expected err")
+	}
+}
@@ -1,6 +1,7 @@
 package dynago
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"time"
@@ -12,10 +13,20 @@ import (
 type PutItem struct {
 	item   Keyer
 	input  *dynamodb.PutItemInput
+	output *PutItemOutput
+	values interface{}
+	ver    *version
+	ts     *timestamps
+	ttl    *time.Duration
 	dynago *Dynago
 	err    error
 }
 
+// PutItemOutput represents the output of a PutItem operation.
+type PutItemOutput struct {
+	ConsumedCapacity *dynamodb.ConsumedCapacity
+}
+
 // PutItem returns a PutItem operation.
 func (d *Dynago) PutItem(item Keyer) *PutItem {
 	return &PutItem{
@@ -37,6 +48,20 @@ func (q *PutItem) ConditionExpression(exp string) *PutItem {
 	return q
 }
 
+// Condition sets the ConditionExpression from the given condition.
+// Placeholders for attribute names and values are generated and
+// added to ExpressionAttributeNames and ExpressionAttributeValues.
+// Conditions of repeated calls are combined with AND.
+func (q *PutItem) Condition(c *Condition) *PutItem {
+	exp, err := q.dynago.condition(q.input.ConditionExpression, c, "c", itemType(q.item), &q.input.ExpressionAttributeNames, &q.input.ExpressionAttributeValues)
+	if err != nil {
+		q.err = fmt.Errorf("dynago: PutItem.Condition: %w", err)
+		return q
+	}
+	q.input.ConditionExpression = exp
+	return q
+}
+
 // ExpressionAttributeValue sets an ExpressionAttributeValue.
 func (q *PutItem) ExpressionAttributeValue(key string, val interface{}, layout ...string) *PutItem {
 	if q.input.ExpressionAttributeValues == nil {
@@ -63,21 +88,110 @@ func (q *PutItem) ExpressionAttributeName(name string, sub string) *PutItem {
 	return q
 }
 
+// TTL sets the TTL attribute of the item to expire after the given
+// duration. The attribute is that of the field with the TTL tag, or
+// Config.TTLAttrName if the item has none. The field itself is not
+// changed.
+func (q *PutItem) TTL(ttl time.Duration) *PutItem {
+	q.ttl = &ttl
+	return q
+}
+
+// ReturnValues sets ReturnValues. The returned attributes are
+// unmarshalled into the item, or into v if it is given. If old
+// attributes are unmarshalled into the item, its version and auto
+// timestamps are left as they were returned.
+func (q *PutItem) ReturnValues(val string, v ...interface{}) *PutItem {
+	q.input.ReturnValues = &val
+	q.values = q.item
+	if len(v) > 0 {
+		q.values = v[0]
+	}
+	return q
+}
+
+// ReturnConsumedCapacity sets ReturnConsumedCapacity.
+func (q *PutItem) ReturnConsumedCapacity(val string) *PutItem {
+	q.input.ReturnConsumedCapacity = &val
+	return q
+}
+
+// Output sets the output to be populated when the operation is
+// executed.
+func (q *PutItem) Output(output *PutItemOutput) *PutItem {
+	q.output = output
+	return q
+}
+
 // Exec exeutes the operation.
 func (q *PutItem) Exec() error {
+	return q.ExecWithContext(context.Background())
+}
+
+// ExecWithContext executes the operation with the given context.
+func (q *PutItem) ExecWithContext(ctx context.Context) error {
 	if q.err != nil {
 		return q.err
 	}
-	var err error
-	q.input.Item, err = q.dynago.Marshal(q.item)
+	input, ver, ts, err := q.prepare()
+	if err != nil {
+		return err
+	}
+	output, err := q.dynago.ddb.PutItemWithContext(ctx, input)
+	if err != nil {
+		return fmt.Errorf("d.ddb.PutItem: %w", versionError(mapError(err), ver))
+	}
+	if q.output != nil {
+		q.output.ConsumedCapacity = output.ConsumedCapacity
+	}
+	if q.values != nil && len(output.Attributes) > 0 {
+		if err := q.dynago.Unmarshal(output.Attributes, q.values); err != nil {
+			return fmt.Errorf("q.dynago.Unmarshal: %w", err)
+		}
+		if q.values == interface{}(q.item) && returnsOld(input.ReturnValues) {
+			return nil
+		}
+	}
+	ts.commit()
+	if ver != nil {
+		ver.commit()
+	}
+	return nil
+}
+
+// prepare returns the input of the operation with the marshalled item.
+// Auto timestamps are set on a copy of the item before it is
+// marshalled. If the item is versioned, the version condition is added
+// and the next version is written.
+func (q *PutItem) prepare() (*dynamodb.PutItemInput, *version, *timestamps, error) {
+	input := *q.input
+	ts, err := q.dynago.autoTimestamps(q.item, true)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	input.Item, err = q.dynago.Marshal(ts.item)
 	if err != nil {
-		return fmt.Errorf("q.dynago.Marshal: %w", err)
+		return nil, nil, nil, fmt.Errorf("q.dynago.Marshal: %w", err)
+	}
+	if q.ttl != nil {
+		name, err := q.dynago.ttlAttrName(itemType(q.item))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		input.Item[name] = q.dynago.expiry(*q.ttl)
 	}
-	_, err = q.dynago.ddb.PutItem(q.input)
+	ver, err := q.dynago.version(q.item)
 	if err != nil {
-		return fmt.Errorf("d.ddb.PutItem: %w", err)
+		return nil, nil, nil, err
 	}
-	return err
+	if ver != nil {
+		input.ExpressionAttributeNames = copyNames(input.ExpressionAttributeNames)
+		input.ExpressionAttributeValues = copyValues(input.ExpressionAttributeValues)
+		e := q.dynago.expression("v", nil, &input.ExpressionAttributeNames, &input.ExpressionAttributeValues)
+		input.ConditionExpression = andCondition(input.ConditionExpression, ver.condition(e))
+		ver.setNext(input.Item)
+	}
+	return &input, ver, ts, nil
 }
 
 // TransactionWriteItem implements the TransactionWriteItemer
@@ -86,17 +200,31 @@ func (q *PutItem) TransactionWriteItem() (*dynamodb.TransactWriteItem, error) {
 	if q.err != nil {
 		return nil, q.err
 	}
-	item, err := q.dynago.Marshal(q.item)
+	input, ver, ts, err := q.prepare()
 	if err != nil {
 		return nil, err
 	}
+	q.ver, q.ts = ver, ts
 	return &dynamodb.TransactWriteItem{
 		Put: &dynamodb.Put{
-			Item:                      item,
-			TableName:                 q.input.TableName,
-			ConditionExpression:       q.input.ConditionExpression,
-			ExpressionAttributeNames:  q.input.ExpressionAttributeNames,
-			ExpressionAttributeValues: q.input.ExpressionAttributeValues,
+			Item:                      input.Item,
+			TableName:                 input.TableName,
+			ConditionExpression:       input.ConditionExpression,
+			ExpressionAttributeNames:  input.ExpressionAttributeNames,
+			ExpressionAttributeValues: input.ExpressionAttributeValues,
 		},
 	}, nil
 }
+
+func (q *PutItem) versioned() bool {
+	return q.ver != nil
+}
+
+func (q *PutItem) commit() {
+	if q.ts != nil {
+		q.ts.commit()
+	}
+	if q.ver != nil {
+		q.ver.commit()
+	}
+}
@@ -1,6 +1,7 @@
 package dynago_test
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"testing"
@@ -169,3 +170,90 @@ func TestPutItemDuration(t *testing.T) {
 	}
 	ddb.done()
 }
+
+func TestPutItemReturnConsumedCapacity(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		*CompositeTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+	}
+	p := Person{Name: "foo"}
+	tableName := "bar"
+	cc := &dynamodb.ConsumedCapacity{TableName: &tableName, CapacityUnits: aws.Float64(1)}
+	ddb.MockPut(&dynamodb.PutItemInput{
+		Item: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Person#foo")},
+		},
+		TableName:              &tableName,
+		ReturnConsumedCapacity: aws.String(dynamodb.ReturnConsumedCapacityTotal),
+	}, &dynamodb.PutItemOutput{ConsumedCapacity: cc})
+	var output dynago.PutItemOutput
+	if err := client.PutItem(&p).
+		TableName(tableName).
+		ReturnConsumedCapacity(dynamodb.ReturnConsumedCapacityTotal).
+		Output(&output).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, cc, output.ConsumedCapacity)
+	ddb.done()
+}
+
+func TestPutItemExecWithContext(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+	}
+	tableName := "bar"
+	ddb.MockPut(&dynamodb.PutItemInput{
+		Item: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Person#foo")},
+		},
+		TableName: &tableName,
+	})
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "foo")
+	p := Person{Name: "foo"}
+	if err := client.PutItem(&p).TableName(tableName).ExecWithContext(ctx); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, "foo", ddb.ctx.Value(ctxKey{}))
+	ddb.done()
+}
+
+func TestPutItemReturnValues(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		*CompositeTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64
+	}
+	p := Person{Name: "foo", Age: 34}
+	tableName := "bar"
+	ddb.MockPut(&dynamodb.PutItemInput{
+		Item: map[string]*dynamodb.AttributeValue{
+			"PK":  {S: aws.String("Person#foo")},
+			"Age": {N: aws.String("34")},
+		},
+		TableName:    &tableName,
+		ReturnValues: aws.String(dynamodb.ReturnValueAllOld),
+	}, &dynamodb.PutItemOutput{
+		Attributes: map[string]*dynamodb.AttributeValue{
+			"PK":  {S: aws.String("Person#foo")},
+			"Age": {N: aws.String("33")},
+		},
+	})
+	var old Person
+	if err := client.PutItem(&p).
+		TableName(tableName).
+		ReturnValues(dynamodb.ReturnValueAllOld, &old).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, Person{Name: "foo", Age: 33}, old)
+	ddb.done()
+}
@@ -1,7 +1,7 @@
 package dynago
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"reflect"
 	"time"
@@ -12,11 +12,37 @@ import (
 // Query represents a Query operation.
 type Query struct {
 	input  *dynamodb.QueryInput
+	output *QueryOutput
 	dynago *Dynago
 	items  interface{}
+	all    bool
+	max    int64
+	pageFn func(lastPage bool) bool
+	expiry bool
 	err    error
 }
 
+// QueryOutput represents the output of a Query operation. When all
+// pages are fetched, Count, ScannedCount and ConsumedCapacity are
+// totals of every page and LastEvaluatedKey is that of the last page.
+type QueryOutput struct {
+	LastEvaluatedKey map[string]*dynamodb.AttributeValue
+	Count            int64
+	ScannedCount     int64
+	ConsumedCapacity *dynamodb.ConsumedCapacity
+}
+
+func (o *QueryOutput) add(output *dynamodb.QueryOutput) {
+	o.LastEvaluatedKey = output.LastEvaluatedKey
+	if output.Count != nil {
+		o.Count += *output.Count
+	}
+	if output.ScannedCount != nil {
+		o.ScannedCount += *output.ScannedCount
+	}
+	addConsumedCapacity(&o.ConsumedCapacity, output.ConsumedCapacity)
+}
+
 // Query returns a Query operation.
 func (d *Dynago) Query(items interface{}) *Query {
 	return &Query{
@@ -65,6 +91,20 @@ func (q *Query) FilterExpression(exp string) *Query {
 	return q
 }
 
+// Filter sets the FilterExpression from the given condition.
+// Placeholders for attribute names and values are generated and
+// added to ExpressionAttributeNames and ExpressionAttributeValues.
+// Conditions of repeated calls are combined with AND.
+func (q *Query) Filter(c *Condition) *Query {
+	exp, err := q.dynago.condition(q.input.FilterExpression, c, "f", itemType(q.items), &q.input.ExpressionAttributeNames, &q.input.ExpressionAttributeValues)
+	if err != nil {
+		q.err = fmt.Errorf("dynago: Query.Filter: %w", err)
+		return q
+	}
+	q.input.FilterExpression = exp
+	return q
+}
+
 // ExclusiveStartKey sets the ExclusiveStartKey.
 func (q *Query) ExclusiveStartKey(key map[string]*dynamodb.AttributeValue) *Query {
 	q.input.ExclusiveStartKey = key
@@ -89,6 +129,21 @@ func (q *Query) KeyConditionExpression(exp string) *Query {
 	return q
 }
 
+// KeyCondition sets the KeyConditionExpression from the given
+// condition. Placeholders for attribute names and values are
+// generated and added to ExpressionAttributeNames and
+// ExpressionAttributeValues.
+func (q *Query) KeyCondition(c *KeyCondition) *Query {
+	e := q.dynago.expression("k", itemType(q.items), &q.input.ExpressionAttributeNames, &q.input.ExpressionAttributeValues)
+	exp, err := c.build(e)
+	if err != nil {
+		q.err = fmt.Errorf("dynago: Query.KeyCondition: %w", err)
+		return q
+	}
+	q.input.KeyConditionExpression = &exp
+	return q
+}
+
 // ExpressionAttributeValue sets an ExpressionAttributeValue.
 func (q *Query) ExpressionAttributeValue(key string, val interface{}, layout ...string) *Query {
 	if q.input.ExpressionAttributeValues == nil {
@@ -115,47 +170,105 @@ func (q *Query) ExpressionAttributeName(name string, sub string) *Query {
 	return q
 }
 
+// FilterExpired adds a filter that excludes items whose TTL attribute
+// has passed, since DynamoDB may take up to a few days to delete
+// expired items.
+func (q *Query) FilterExpired() *Query {
+	q.expiry = true
+	return q
+}
+
+// ReturnConsumedCapacity sets ReturnConsumedCapacity.
+func (q *Query) ReturnConsumedCapacity(val string) *Query {
+	q.input.ReturnConsumedCapacity = &val
+	return q
+}
+
+// Output sets the output to be populated when the operation is
+// executed.
+func (q *Query) Output(output *QueryOutput) *Query {
+	q.output = output
+	return q
+}
+
+// All sets the operation to fetch every page of results instead of
+// only the first. Items of each page are appended to the items. An
+// optional max limits the number of items collected.
+func (q *Query) All(max ...int64) *Query {
+	q.all = true
+	if len(max) > 0 {
+		q.max = max[0]
+	}
+	return q
+}
+
+// PageFunc sets a function that is called after each page of results
+// is appended to the items. Every page is fetched until fn returns
+// false.
+func (q *Query) PageFunc(fn func(lastPage bool) bool) *Query {
+	q.all = true
+	q.pageFn = fn
+	return q
+}
+
 // Exec executes the operation.
 func (q *Query) Exec() error {
+	return q.ExecWithContext(context.Background())
+}
+
+// ExecWithContext executes the operation with the given context.
+func (q *Query) ExecWithContext(ctx context.Context) error {
 	if q.err != nil {
 		return q.err
 	}
-	rv := reflect.ValueOf(q.items)
-	if rv.Kind() != reflect.Pointer {
-		return errors.New("dynago: dynago.Query.Exec: v must be pointer")
+	s, err := newItemSlice(q.items, "Query")
+	if err != nil {
+		return err
 	}
-	for rv.Kind() == reflect.Pointer {
-		rv = reflect.Indirect(rv)
+	if q.output != nil {
+		*q.output = QueryOutput{}
 	}
-	var err error
-	output, err := q.dynago.ddb.Query(q.input)
-	if err != nil {
-		return fmt.Errorf("d.ddb.Query: %w", err)
-	}
-	rt := reflect.TypeOf(q.items)
-	for rt.Kind() == reflect.Pointer {
-		rt = rt.Elem()
-	}
-	ft := rt.Elem()
-	indirect := true
-	if ft.Kind() == reflect.Pointer {
-		ft = ft.Elem()
-		indirect = false
-	}
-	if ft.Kind() == reflect.Pointer {
-		return errors.New("dynago: dynago.Query.Exec: elements of v can not be pointers to pointers")
-	}
-	s := reflect.MakeSlice(rt, len(output.Items), len(output.Items))
-	for i, item := range output.Items {
-		iv := reflect.New(ft)
-		if err := q.dynago.Unmarshal(item, iv.Interface()); err != nil {
-			return fmt.Errorf("q.dynago.Unmarshal: %w", err)
+	input := *q.input
+	if q.expiry {
+		input.FilterExpression, err = q.dynago.expiredFilter(itemType(q.items), input.FilterExpression, &input.ExpressionAttributeNames, &input.ExpressionAttributeValues)
+		if err != nil {
+			return err
+		}
+	}
+	if !q.all {
+		output, err := q.dynago.ddb.QueryWithContext(ctx, &input)
+		if err != nil {
+			return fmt.Errorf("d.ddb.Query: %w", mapError(err))
+		}
+		if q.output != nil {
+			q.output.add(output)
+		}
+		return s.set(q.dynago, output.Items)
+	}
+	if err := s.set(q.dynago, nil); err != nil {
+		return err
+	}
+	for {
+		if q.max > 0 {
+			remaining := q.max - int64(s.val.Len())
+			if q.input.Limit == nil || *q.input.Limit > remaining {
+				input.Limit = &remaining
+			}
+		}
+		output, err := q.dynago.ddb.QueryWithContext(ctx, &input)
+		if err != nil {
+			return fmt.Errorf("d.ddb.Query: %w", mapError(err))
+		}
+		if q.output != nil {
+			q.output.add(output)
+		}
+		if err := s.append(q.dynago, output.Items); err != nil {
+			return err
 		}
-		if indirect {
-			iv = reflect.Indirect(iv)
+		lastPage := len(output.LastEvaluatedKey) == 0 || (q.max > 0 && int64(s.val.Len()) >= q.max)
+		if q.pageFn != nil && !q.pageFn(lastPage) || lastPage {
+			return nil
 		}
-		s.Index(i).Set(iv)
+		input.ExclusiveStartKey = output.LastEvaluatedKey
 	}
-	rv.Set(s)
-	return nil
 }
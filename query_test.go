@@ -1045,3 +1045,222 @@ func TestExpFloat64(t *testing.T) {
 	assertEq(t, want, got)
 	ddb.done()
 }
+
+func TestQueryAll(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64  `attr:"SK"`
+	}
+	tableName := "bar"
+	lek := map[string]*dynamodb.AttributeValue{
+		"PK": {S: aws.String("Person#foo")},
+		"SK": {N: aws.String("33")},
+	}
+	ddb.MockQuery(&dynamodb.QueryInput{
+		KeyConditionExpression: aws.String("PK = :pk"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":pk": {S: aws.String("Person#foo")},
+		},
+		TableName:      &tableName,
+		ConsistentRead: aws.Bool(false),
+	}, &dynamodb.QueryOutput{
+		Items:            []map[string]*dynamodb.AttributeValue{lek},
+		LastEvaluatedKey: lek,
+	})
+	ddb.MockQuery(&dynamodb.QueryInput{
+		KeyConditionExpression: aws.String("PK = :pk"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":pk": {S: aws.String("Person#foo")},
+		},
+		TableName:         &tableName,
+		ConsistentRead:    aws.Bool(false),
+		ExclusiveStartKey: lek,
+	}, &dynamodb.QueryOutput{
+		Items: []map[string]*dynamodb.AttributeValue{
+			{
+				"PK": {S: aws.String("Person#foo")},
+				"SK": {N: aws.String("34")},
+			},
+		},
+	})
+	got := []Person{{Name: "stale"}}
+	if err := client.Query(&got).
+		TableName(tableName).
+		KeyConditionExpression("PK = :pk").
+		ExpressionAttributeValue(":pk", "Person#foo").
+		All().
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, []Person{{Name: "foo", Age: 33}, {Name: "foo", Age: 34}}, got)
+	ddb.done()
+}
+
+func TestQueryAllMax(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64  `attr:"SK"`
+	}
+	tableName := "bar"
+	lek := map[string]*dynamodb.AttributeValue{
+		"PK": {S: aws.String("Person#foo")},
+		"SK": {N: aws.String("33")},
+	}
+	ddb.MockQuery(&dynamodb.QueryInput{
+		TableName:      &tableName,
+		ConsistentRead: aws.Bool(false),
+		Limit:          aws.Int64(2),
+	}, &dynamodb.QueryOutput{
+		Items:            []map[string]*dynamodb.AttributeValue{lek},
+		LastEvaluatedKey: lek,
+	})
+	ddb.MockQuery(&dynamodb.QueryInput{
+		TableName:         &tableName,
+		ConsistentRead:    aws.Bool(false),
+		Limit:             aws.Int64(1),
+		ExclusiveStartKey: lek,
+	}, &dynamodb.QueryOutput{
+		Items: []map[string]*dynamodb.AttributeValue{
+			{
+				"PK": {S: aws.String("Person#foo")},
+				"SK": {N: aws.String("34")},
+			},
+		},
+		LastEvaluatedKey: lek,
+	})
+	var got []*Person
+	if err := client.Query(&got).
+		TableName(tableName).
+		All(2).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, []*Person{{Name: "foo", Age: 33}, {Name: "foo", Age: 34}}, got)
+	ddb.done()
+}
+
+func TestQueryPageFunc(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64  `attr:"SK"`
+	}
+	tableName := "bar"
+	lek := map[string]*dynamodb.AttributeValue{
+		"PK": {S: aws.String("Person#foo")},
+		"SK": {N: aws.String("33")},
+	}
+	ddb.MockQuery(&dynamodb.QueryInput{
+		TableName:      &tableName,
+		ConsistentRead: aws.Bool(false),
+	}, &dynamodb.QueryOutput{
+		Items:            []map[string]*dynamodb.AttributeValue{lek},
+		LastEvaluatedKey: lek,
+	})
+	var got []Person
+	pages := 0
+	if err := client.Query(&got).
+		TableName(tableName).
+		PageFunc(func(lastPage bool) bool {
+			pages++
+			assertEq(t, false, lastPage)
+			return false
+		}).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, 1, pages)
+	assertEq(t, []Person{{Name: "foo", Age: 33}}, got)
+	ddb.done()
+}
+
+func TestQueryOutput(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64  `attr:"SK"`
+	}
+	tableName := "bar"
+	lek := map[string]*dynamodb.AttributeValue{
+		"PK": {S: aws.String("Person#foo")},
+		"SK": {N: aws.String("33")},
+	}
+	ddb.MockQuery(&dynamodb.QueryInput{
+		TableName:              &tableName,
+		ConsistentRead:         aws.Bool(false),
+		ReturnConsumedCapacity: aws.String(dynamodb.ReturnConsumedCapacityTotal),
+	}, &dynamodb.QueryOutput{
+		Items:            []map[string]*dynamodb.AttributeValue{lek},
+		LastEvaluatedKey: lek,
+		Count:            aws.Int64(1),
+		ScannedCount:     aws.Int64(2),
+		ConsumedCapacity: &dynamodb.ConsumedCapacity{CapacityUnits: aws.Float64(0.5)},
+	})
+	ddb.MockQuery(&dynamodb.QueryInput{
+		TableName:              &tableName,
+		ConsistentRead:         aws.Bool(false),
+		ReturnConsumedCapacity: aws.String(dynamodb.ReturnConsumedCapacityTotal),
+		ExclusiveStartKey:      lek,
+	}, &dynamodb.QueryOutput{
+		Count:            aws.Int64(0),
+		ScannedCount:     aws.Int64(3),
+		ConsumedCapacity: &dynamodb.ConsumedCapacity{CapacityUnits: aws.Float64(1)},
+	})
+	var got []Person
+	var output dynago.QueryOutput
+	if err := client.Query(&got).
+		TableName(tableName).
+		ReturnConsumedCapacity(dynamodb.ReturnConsumedCapacityTotal).
+		Output(&output).
+		All().
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, dynago.QueryOutput{
+		Count:            1,
+		ScannedCount:     5,
+		ConsumedCapacity: &dynamodb.ConsumedCapacity{CapacityUnits: aws.Float64(1.5)},
+	}, output)
+	ddb.done()
+}
+
+func TestQueryOutputLastEvaluatedKey(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64  `attr:"SK"`
+	}
+	tableName := "bar"
+	lek := map[string]*dynamodb.AttributeValue{
+		"PK": {S: aws.String("Person#foo")},
+		"SK": {N: aws.String("33")},
+	}
+	ddb.MockQuery(&dynamodb.QueryInput{
+		TableName:      &tableName,
+		ConsistentRead: aws.Bool(false),
+		Limit:          aws.Int64(1),
+	}, &dynamodb.QueryOutput{
+		Items:            []map[string]*dynamodb.AttributeValue{lek},
+		LastEvaluatedKey: lek,
+		Count:            aws.Int64(1),
+		ScannedCount:     aws.Int64(1),
+	})
+	var got []Person
+	var output dynago.QueryOutput
+	if err := client.Query(&got).
+		TableName(tableName).
+		Limit(1).
+		Output(&output).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, dynago.QueryOutput{LastEvaluatedKey: lek, Count: 1, ScannedCount: 1}, output)
+	ddb.done()
+}
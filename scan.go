@@ -1,9 +1,13 @@
 package dynago
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/service/dynamodb"
@@ -11,16 +15,79 @@ import (
 
 // Scan represents a Scan operation.
 type Scan struct {
-	input  *dynamodb.ScanInput
-	output *ScanOutput
-	dynago *Dynago
-	items  interface{}
-	err    error
+	input       *dynamodb.ScanInput
+	output      *ScanOutput
+	dynago      *Dynago
+	items       interface{}
+	all         bool
+	max         int64
+	pageFn      func(lastPage bool) bool
+	concurrency int
+	expiry      bool
+	err         error
 }
 
-// ScanOutput represents the output of a scan command.
+// ScanOutput represents the output of a scan command. When all pages
+// are fetched, Count, ScannedCount and ConsumedCapacity are totals of
+// every page and LastEvaluatedKey is that of the last page.
 type ScanOutput struct {
 	LastEvaluatedKey map[string]*dynamodb.AttributeValue
+	Count            int64
+	ScannedCount     int64
+	ConsumedCapacity *dynamodb.ConsumedCapacity
+}
+
+func (o *ScanOutput) add(output *dynamodb.ScanOutput) {
+	o.LastEvaluatedKey = output.LastEvaluatedKey
+	if output.Count != nil {
+		o.Count += *output.Count
+	}
+	if output.ScannedCount != nil {
+		o.ScannedCount += *output.ScannedCount
+	}
+	addConsumedCapacity(&o.ConsumedCapacity, output.ConsumedCapacity)
+}
+
+// ScanSegmentError is returned when scanning a segment of a parallel
+// scan fails.
+type ScanSegmentError struct {
+	Segment int64
+	Err     error
+}
+
+// Error implements the error interface.
+func (e *ScanSegmentError) Error() string {
+	return fmt.Sprintf("segment %d: %s", e.Segment, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ScanSegmentError) Unwrap() error {
+	return e.Err
+}
+
+// ParallelScanError is returned when scanning one or more segments of
+// a parallel scan fails.
+type ParallelScanError struct {
+	Segments []*ScanSegmentError
+}
+
+// Error implements the error interface.
+func (e *ParallelScanError) Error() string {
+	msgs := make([]string, len(e.Segments))
+	for i, seg := range e.Segments {
+		msgs[i] = seg.Error()
+	}
+	return fmt.Sprintf("dynago: parallel scan failed: %s", strings.Join(msgs, "; "))
+}
+
+// Is reports whether the error of any segment matches the target.
+func (e *ParallelScanError) Is(target error) bool {
+	for _, seg := range e.Segments {
+		if errors.Is(seg.Err, target) {
+			return true
+		}
+	}
+	return false
 }
 
 // Scan returns a Scan operation.
@@ -83,6 +150,28 @@ func (q *Scan) FilterExpression(exp string) *Scan {
 	return q
 }
 
+// Filter sets the FilterExpression from the given condition.
+// Placeholders for attribute names and values are generated and
+// added to ExpressionAttributeNames and ExpressionAttributeValues.
+// Conditions of repeated calls are combined with AND.
+func (q *Scan) Filter(c *Condition) *Scan {
+	exp, err := q.dynago.condition(q.input.FilterExpression, c, "f", itemType(q.items), &q.input.ExpressionAttributeNames, &q.input.ExpressionAttributeValues)
+	if err != nil {
+		q.err = fmt.Errorf("dynago: Scan.Filter: %w", err)
+		return q
+	}
+	q.input.FilterExpression = exp
+	return q
+}
+
+// FilterExpired adds a filter that excludes items whose TTL attribute
+// has passed, since DynamoDB may take up to a few days to delete
+// expired items.
+func (q *Scan) FilterExpired() *Scan {
+	q.expiry = true
+	return q
+}
+
 // ExclusiveStartKey sets the ExclusiveStartKey.
 func (q *Scan) ExclusiveStartKey(key map[string]*dynamodb.AttributeValue) *Scan {
 	q.input.ExclusiveStartKey = key
@@ -121,52 +210,236 @@ func (q *Scan) ExpressionAttributeName(name string, sub string) *Scan {
 	return q
 }
 
+// ReturnConsumedCapacity sets ReturnConsumedCapacity.
+func (q *Scan) ReturnConsumedCapacity(val string) *Scan {
+	q.input.ReturnConsumedCapacity = &val
+	return q
+}
+
+// Output sets the output to be populated when the operation is
+// executed.
 func (q *Scan) Output(output *ScanOutput) *Scan {
 	q.output = output
 	return q
 }
 
+// All sets the operation to fetch every page of results instead of
+// only the first. Items of each page are appended to the items. An
+// optional max limits the number of items collected.
+func (q *Scan) All(max ...int64) *Scan {
+	q.all = true
+	if len(max) > 0 {
+		q.max = max[0]
+	}
+	return q
+}
+
+// PageFunc sets a function that is called after each page of results
+// is appended to the items. Every page is fetched until fn returns
+// false.
+func (q *Scan) PageFunc(fn func(lastPage bool) bool) *Scan {
+	q.all = true
+	q.pageFn = fn
+	return q
+}
+
 // Exec executes the operation.
 func (q *Scan) Exec() error {
-	rv := reflect.ValueOf(q.items)
-	if rv.Kind() != reflect.Pointer {
-		return errors.New("dynago: dynago.Scan.Exec: v must be pointer")
+	return q.ExecWithContext(context.Background())
+}
+
+// ExecWithContext executes the operation with the given context.
+func (q *Scan) ExecWithContext(ctx context.Context) error {
+	if q.err != nil {
+		return q.err
 	}
-	for rv.Kind() == reflect.Pointer {
-		rv = reflect.Indirect(rv)
+	s, err := newItemSlice(q.items, "Scan")
+	if err != nil {
+		return err
 	}
-	var err error
-	output, err := q.dynago.ddb.Scan(q.input)
+	if q.output != nil {
+		*q.output = ScanOutput{}
+	}
+	input, err := q.prepare()
+	if err != nil {
+		return err
+	}
+	if !q.all {
+		output, err := q.dynago.ddb.ScanWithContext(ctx, input)
+		if err != nil {
+			return fmt.Errorf("d.ddb.Scan: %w", mapError(err))
+		}
+		if q.output != nil {
+			q.output.add(output)
+		}
+		return s.set(q.dynago, output.Items)
+	}
+	if err := s.set(q.dynago, nil); err != nil {
+		return err
+	}
+	for {
+		if q.max > 0 {
+			remaining := q.max - int64(s.val.Len())
+			if q.input.Limit == nil || *q.input.Limit > remaining {
+				input.Limit = &remaining
+			}
+		}
+		output, err := q.dynago.ddb.ScanWithContext(ctx, input)
+		if err != nil {
+			return fmt.Errorf("d.ddb.Scan: %w", mapError(err))
+		}
+		if q.output != nil {
+			q.output.add(output)
+		}
+		if err := s.append(q.dynago, output.Items); err != nil {
+			return err
+		}
+		lastPage := len(output.LastEvaluatedKey) == 0 || (q.max > 0 && int64(s.val.Len()) >= q.max)
+		if q.pageFn != nil && !q.pageFn(lastPage) || lastPage {
+			return nil
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+}
+
+// Concurrency sets the maximum number of segments ExecParallel scans
+// at the same time. Defaults to TotalSegments.
+func (q *Scan) Concurrency(n int) *Scan {
+	q.concurrency = n
+	return q
+}
+
+// ExecParallel executes the operation as a parallel scan. Each of
+// TotalSegments segments is scanned in its own goroutine and paged
+// through until it is exhausted. Every item is unmarshalled into a
+// new element of the type of the items and passed to fn, which may be
+// called concurrently. If fn is nil, the items are appended to the
+// items instead. Returning false from fn stops every segment. If any
+// segment fails, the others are stopped and a *ParallelScanError is
+// returned.
+func (q *Scan) ExecParallel(fn func(item interface{}) bool) error {
+	return q.ExecParallelWithContext(context.Background(), fn)
+}
+
+// ExecParallelWithContext executes the operation as a parallel scan
+// with the given context. If the context is canceled, every segment
+// is stopped and the context's error is returned.
+func (q *Scan) ExecParallelWithContext(parent context.Context, fn func(item interface{}) bool) error {
+	if q.err != nil {
+		return q.err
+	}
+	if q.input.TotalSegments == nil || *q.input.TotalSegments < 1 {
+		return errors.New("dynago: dynago.Scan.ExecParallel: TotalSegments must be set")
+	}
+	s, err := newItemSlice(q.items, "Scan")
 	if err != nil {
-		return fmt.Errorf("d.ddb.GetItem: %w", err)
+		return err
+	}
+	total := *q.input.TotalSegments
+	concurrency := int64(q.concurrency)
+	if concurrency <= 0 || concurrency > total {
+		concurrency = total
+	}
+	var mtx sync.Mutex
+	if fn == nil {
+		if err := s.set(q.dynago, nil); err != nil {
+			return err
+		}
+		fn = func(item interface{}) bool {
+			mtx.Lock()
+			defer mtx.Unlock()
+			s.val.Set(reflect.Append(s.val, reflect.ValueOf(item)))
+			return true
+		}
 	}
 	if q.output != nil {
-		q.output.LastEvaluatedKey = output.LastEvaluatedKey
+		*q.output = ScanOutput{}
+	}
+	input, err := q.prepare()
+	if err != nil {
+		return err
+	}
+	ctx, halt := context.WithCancel(parent)
+	defer halt()
+	var segErrs []*ScanSegmentError
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+start:
+	for segment := int64(0); segment < total; segment++ {
+		select {
+		case <-ctx.Done():
+			break start
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		go func(segment int64) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := q.scanSegment(ctx, input, segment, s, fn, &mtx, halt); err != nil && ctx.Err() == nil {
+				mtx.Lock()
+				segErrs = append(segErrs, &ScanSegmentError{Segment: segment, Err: err})
+				mtx.Unlock()
+				halt()
+			}
+		}(segment)
 	}
-	rt := reflect.TypeOf(q.items)
-	for rt.Kind() == reflect.Pointer {
-		rt = rt.Elem()
+	wg.Wait()
+	if q.output != nil {
+		q.output.LastEvaluatedKey = nil
 	}
-	ft := rt.Elem()
-	indirect := true
-	if ft.Kind() == reflect.Pointer {
-		ft = ft.Elem()
-		indirect = false
+	if err := parent.Err(); err != nil {
+		return err
 	}
-	if ft.Kind() == reflect.Pointer {
-		return errors.New("dynago: dynago.Scan.Exec: elements of v can not be pointers to pointers")
+	if len(segErrs) > 0 {
+		sort.Slice(segErrs, func(i, j int) bool {
+			return segErrs[i].Segment < segErrs[j].Segment
+		})
+		return &ParallelScanError{Segments: segErrs}
 	}
-	s := reflect.MakeSlice(rt, len(output.Items), len(output.Items))
-	for i, item := range output.Items {
-		iv := reflect.New(ft)
-		if err := q.dynago.Unmarshal(item, iv.Interface()); err != nil {
-			return fmt.Errorf("q.dynago.Unmarshal: %w", err)
+	return nil
+}
+
+func (q *Scan) scanSegment(ctx context.Context, in *dynamodb.ScanInput, segment int64, s *itemSlice, fn func(interface{}) bool, mtx *sync.Mutex, halt func()) error {
+	input := *in
+	input.Segment = &segment
+	for ctx.Err() == nil {
+		output, err := q.dynago.ddb.ScanWithContext(ctx, &input)
+		if err != nil {
+			return fmt.Errorf("d.ddb.Scan: %w", mapError(err))
+		}
+		if q.output != nil {
+			mtx.Lock()
+			q.output.add(output)
+			mtx.Unlock()
 		}
-		if indirect {
-			iv = reflect.Indirect(iv)
+		for _, item := range output.Items {
+			iv, err := s.unmarshal(q.dynago, item)
+			if err != nil {
+				return err
+			}
+			if !fn(iv.Interface()) {
+				halt()
+				return nil
+			}
 		}
-		s.Index(i).Set(iv)
+		if len(output.LastEvaluatedKey) == 0 {
+			return nil
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
 	}
-	rv.Set(s)
 	return nil
 }
+
+// prepare returns a copy of the input of the operation, with the
+// filter of expired items added if it is set.
+func (q *Scan) prepare() (*dynamodb.ScanInput, error) {
+	input := *q.input
+	if q.expiry {
+		var err error
+		input.FilterExpression, err = q.dynago.expiredFilter(itemType(q.items), input.FilterExpression, &input.ExpressionAttributeNames, &input.ExpressionAttributeValues)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &input, nil
+}
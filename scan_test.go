@@ -1,9 +1,14 @@
 package dynago_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
@@ -490,3 +495,333 @@ func TestScanIndex(t *testing.T) {
 	assertEq(t, want, got)
 	ddb.done()
 }
+
+func TestScanAll(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64
+	}
+	tableName := "bar"
+	lek := map[string]*dynamodb.AttributeValue{
+		"PK": {S: aws.String("Person#foo")},
+	}
+	ddb.MockScan(&dynamodb.ScanInput{
+		TableName:      &tableName,
+		ConsistentRead: aws.Bool(false),
+	}, &dynamodb.ScanOutput{
+		Items: []map[string]*dynamodb.AttributeValue{
+			{"PK": {S: aws.String("Person#foo")}, "Age": {N: aws.String("33")}},
+		},
+		LastEvaluatedKey: lek,
+	})
+	ddb.MockScan(&dynamodb.ScanInput{
+		TableName:         &tableName,
+		ConsistentRead:    aws.Bool(false),
+		ExclusiveStartKey: lek,
+	}, &dynamodb.ScanOutput{
+		Items: []map[string]*dynamodb.AttributeValue{
+			{"PK": {S: aws.String("Person#bar")}, "Age": {N: aws.String("34")}},
+		},
+	})
+	var got []Person
+	var output dynago.ScanOutput
+	lastPages := []bool{}
+	if err := client.Scan(&got).
+		TableName(tableName).
+		Output(&output).
+		PageFunc(func(lastPage bool) bool {
+			lastPages = append(lastPages, lastPage)
+			return true
+		}).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, []Person{{Name: "foo", Age: 33}, {Name: "bar", Age: 34}}, got)
+	assertEq(t, []bool{false, true}, lastPages)
+	assertEq(t, map[string]*dynamodb.AttributeValue(nil), output.LastEvaluatedKey)
+	ddb.done()
+}
+
+func TestScanAllMax(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64
+	}
+	tableName := "bar"
+	lek := map[string]*dynamodb.AttributeValue{
+		"PK": {S: aws.String("Person#foo")},
+	}
+	ddb.MockScan(&dynamodb.ScanInput{
+		TableName:      &tableName,
+		ConsistentRead: aws.Bool(false),
+		Limit:          aws.Int64(1),
+	}, &dynamodb.ScanOutput{
+		Items: []map[string]*dynamodb.AttributeValue{
+			{"PK": {S: aws.String("Person#foo")}, "Age": {N: aws.String("33")}},
+		},
+		LastEvaluatedKey: lek,
+	})
+	var got []Person
+	var output dynago.ScanOutput
+	if err := client.Scan(&got).
+		TableName(tableName).
+		Limit(10).
+		Output(&output).
+		All(1).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, []Person{{Name: "foo", Age: 33}}, got)
+	assertEq(t, lek, output.LastEvaluatedKey)
+	ddb.done()
+}
+
+func TestScanOutputConsumedCapacity(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64
+	}
+	tableName := "bar"
+	cc := &dynamodb.ConsumedCapacity{TableName: &tableName, CapacityUnits: aws.Float64(0.5)}
+	ddb.MockScan(&dynamodb.ScanInput{
+		TableName:              &tableName,
+		ConsistentRead:         aws.Bool(false),
+		ReturnConsumedCapacity: aws.String(dynamodb.ReturnConsumedCapacityTotal),
+	}, &dynamodb.ScanOutput{
+		Count:            aws.Int64(0),
+		ScannedCount:     aws.Int64(4),
+		ConsumedCapacity: cc,
+	})
+	var got []Person
+	var output dynago.ScanOutput
+	if err := client.Scan(&got).
+		TableName(tableName).
+		ReturnConsumedCapacity(dynamodb.ReturnConsumedCapacityTotal).
+		Output(&output).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, dynago.ScanOutput{ScannedCount: 4, ConsumedCapacity: cc}, output)
+	ddb.done()
+}
+
+func TestScanExecParallel(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64
+	}
+	tableName := "bar"
+	lek := map[string]*dynamodb.AttributeValue{
+		"PK": {S: aws.String("Person#b")},
+	}
+	person := func(name string, age int64) map[string]*dynamodb.AttributeValue {
+		return map[string]*dynamodb.AttributeValue{
+			"PK":  {S: aws.String("Person#" + name)},
+			"Age": {N: aws.String(strconv.FormatInt(age, 10))},
+		}
+	}
+	ddb.MockScan(&dynamodb.ScanInput{
+		TableName:      &tableName,
+		ConsistentRead: aws.Bool(false),
+		Segment:        aws.Int64(0),
+		TotalSegments:  aws.Int64(2),
+	}, &dynamodb.ScanOutput{
+		Items: []map[string]*dynamodb.AttributeValue{person("a", 1)},
+	})
+	ddb.MockScan(&dynamodb.ScanInput{
+		TableName:      &tableName,
+		ConsistentRead: aws.Bool(false),
+		Segment:        aws.Int64(1),
+		TotalSegments:  aws.Int64(2),
+	}, &dynamodb.ScanOutput{
+		Items:            []map[string]*dynamodb.AttributeValue{person("b", 2)},
+		LastEvaluatedKey: lek,
+	})
+	ddb.MockScan(&dynamodb.ScanInput{
+		TableName:         &tableName,
+		ConsistentRead:    aws.Bool(false),
+		Segment:           aws.Int64(1),
+		TotalSegments:     aws.Int64(2),
+		ExclusiveStartKey: lek,
+	}, &dynamodb.ScanOutput{
+		Items: []map[string]*dynamodb.AttributeValue{person("c", 3)},
+	})
+	var got []*Person
+	if err := client.Scan(&got).
+		TableName(tableName).
+		TotalSegments(2).
+		ExecParallel(nil); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	sort.Slice(got, func(i, j int) bool { return got[i].Age < got[j].Age })
+	assertEq(t, []*Person{{Name: "a", Age: 1}, {Name: "b", Age: 2}, {Name: "c", Age: 3}}, got)
+	ddb.done()
+}
+
+func TestScanExecParallelFunc(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		Name string `attr:"PK" fmt:"Person#{}"`
+	}
+	tableName := "bar"
+	for segment := int64(0); segment < 3; segment++ {
+		ddb.MockScan(&dynamodb.ScanInput{
+			TableName:      &tableName,
+			ConsistentRead: aws.Bool(false),
+			Segment:        aws.Int64(segment),
+			TotalSegments:  aws.Int64(3),
+		}, &dynamodb.ScanOutput{
+			Items: []map[string]*dynamodb.AttributeValue{
+				{"PK": {S: aws.String(fmt.Sprintf("Person#%d", segment))}},
+			},
+		})
+	}
+	var mtx sync.Mutex
+	var names []string
+	if err := client.Scan(&[]Person{}).
+		TableName(tableName).
+		TotalSegments(3).
+		Concurrency(2).
+		ExecParallel(func(item interface{}) bool {
+			mtx.Lock()
+			defer mtx.Unlock()
+			names = append(names, item.(Person).Name)
+			return true
+		}); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	sort.Strings(names)
+	assertEq(t, []string{"0", "1", "2"}, names)
+	ddb.done()
+}
+
+func TestScanExecParallelStop(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		Name string `attr:"PK" fmt:"Person#{}"`
+	}
+	tableName := "bar"
+	ddb.MockScan(&dynamodb.ScanInput{
+		TableName:      &tableName,
+		ConsistentRead: aws.Bool(false),
+		Segment:        aws.Int64(0),
+		TotalSegments:  aws.Int64(2),
+	}, &dynamodb.ScanOutput{
+		Items: []map[string]*dynamodb.AttributeValue{
+			{"PK": {S: aws.String("Person#a")}},
+			{"PK": {S: aws.String("Person#b")}},
+		},
+		LastEvaluatedKey: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Person#b")},
+		},
+	})
+	calls := 0
+	if err := client.Scan(&[]*Person{}).
+		TableName(tableName).
+		TotalSegments(2).
+		Concurrency(1).
+		ExecParallel(func(item interface{}) bool {
+			calls++
+			return false
+		}); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, 1, calls)
+	ddb.done()
+}
+
+func TestScanExecParallelError(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		Name string `attr:"PK" fmt:"Person#{}"`
+	}
+	tableName := "bar"
+	scanErr := errors.New("foo")
+	ddb.MockScan(&dynamodb.ScanInput{
+		TableName:      &tableName,
+		ConsistentRead: aws.Bool(false),
+		Segment:        aws.Int64(0),
+		TotalSegments:  aws.Int64(1),
+	}, nil, scanErr)
+	err := client.Scan(&[]*Person{}).
+		TableName(tableName).
+		TotalSegments(1).
+		ExecParallel(nil)
+	var psErr *dynago.ParallelScanError
+	if !errors.As(err, &psErr) {
+		t.Fatalf("want *ParallelScanError; got %v", err)
+	}
+	assertEq(t, 1, len(psErr.Segments))
+	assertEq(t, int64(0), psErr.Segments[0].Segment)
+	if !errors.Is(err, scanErr) {
+		t.Fatalf("want errors.Is %v", scanErr)
+	}
+	ddb.done()
+}
+
+func TestScanExecParallelNoSegments(t *testing.T) {
+	client := dynago.New(mock(t))
+	type Person struct {
+		Name string `attr:"PK" fmt:"Person#{}"`
+	}
+	if err := client.Scan(&[]*Person{}).ExecParallel(nil); err == nil {
+		t.Fatalf("expected err")
+	}
+}
+
+func TestScanExecParallelWithContextCanceled(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		Name string `attr:"PK" fmt:"Person#{}"`
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := client.Scan(&[]*Person{}).
+		TableName("bar").
+		TotalSegments(4).
+		ExecParallelWithContext(ctx, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("want context.Canceled; got %v", err)
+	}
+	ddb.done()
+}
+
+func TestScanExpressionAttributeValueUnixMilli(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Event struct {
+		*SimpleTable
+		ID string    `attr:"PK"`
+		At time.Time `layout:"unixmilli"`
+	}
+	tableName := "bar"
+	ddb.MockScan(&dynamodb.ScanInput{
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":at": {N: aws.String("1680674828009")},
+		},
+		FilterExpression: aws.String("At > :at"),
+		TableName:        &tableName,
+		ConsistentRead:   aws.Bool(false),
+	}, &dynamodb.ScanOutput{})
+	var got []*Event
+	if err := client.Scan(&got).
+		TableName(tableName).
+		ExpressionAttributeValue(":at", time.Date(2023, 4, 5, 6, 7, 8, 9000000, time.UTC), dynago.LayoutUnixMilli).
+		FilterExpression("At > :at").
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	ddb.done()
+}
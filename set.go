@@ -0,0 +1,151 @@
+package dynago
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+var emptyStructType = reflect.TypeOf(struct{}{})
+
+// setType returns the DynamoDB set type of sets with elements of the
+// type ty, or "" if they can not be in a set.
+func setType(ty reflect.Type) string {
+	ty = derefType(ty)
+	switch ty.Kind() {
+	case reflect.String:
+		return "SS"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return "NS"
+	case reflect.Slice:
+		if ty.Elem().Kind() == reflect.Uint8 {
+			return "BS"
+		}
+	}
+	return ""
+}
+
+// isSetMap reports whether ty is a map[T]struct{} used as a set.
+func isSetMap(ty reflect.Type) bool {
+	return ty.Kind() == reflect.Map && ty.Elem() == emptyStructType
+}
+
+// marshalSet marshals a slice, or the keys of a map[T]struct{}, into
+// a set of the given type. Duplicate elements are dropped. Nil is
+// returned if the set is empty, since DynamoDB does not allow empty
+// sets.
+func (d *Dynago) marshalSet(v reflect.Value, attrType string, layout string) (*dynamodb.AttributeValue, error) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	var els []reflect.Value
+	switch {
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			els = append(els, v.Index(i))
+		}
+	case isSetMap(v.Type()):
+		els = v.MapKeys()
+	default:
+		return nil, fmt.Errorf("dynago: %s can not be marshalled as a %s set", v.Type(), attrType)
+	}
+	av := &dynamodb.AttributeValue{}
+	seen := make(map[string]bool)
+	for _, el := range els {
+		elAv, err := d.simpleMarshal(el, layout)
+		if err != nil {
+			return nil, fmt.Errorf("d.simpleMarshal: %w", err)
+		}
+		switch {
+		case elAv == nil:
+			continue
+		case attrType == "SS" && elAv.S != nil:
+			if !seen[*elAv.S] {
+				av.SS = append(av.SS, elAv.S)
+			}
+			seen[*elAv.S] = true
+		case attrType == "NS" && elAv.N != nil:
+			if !seen[*elAv.N] {
+				av.NS = append(av.NS, elAv.N)
+			}
+			seen[*elAv.N] = true
+		case attrType == "BS" && elAv.B != nil:
+			if !seen[string(elAv.B)] {
+				av.BS = append(av.BS, elAv.B)
+			}
+			seen[string(elAv.B)] = true
+		default:
+			return nil, fmt.Errorf("dynago: elements of type %s can not be in a %s set", el.Type(), attrType)
+		}
+	}
+	if len(av.SS) == 0 && len(av.NS) == 0 && len(av.BS) == 0 {
+		return nil, nil
+	}
+	if v.Kind() == reflect.Map {
+		// Map keys are in random order.
+		sort.Slice(av.SS, func(i, j int) bool { return *av.SS[i] < *av.SS[j] })
+		sort.Slice(av.NS, func(i, j int) bool { return *av.NS[i] < *av.NS[j] })
+		sort.Slice(av.BS, func(i, j int) bool { return bytes.Compare(av.BS[i], av.BS[j]) < 0 })
+	}
+	return av, nil
+}
+
+// unmarshalSet unmarshals a string, number or binary set into a slice
+// or a map[T]struct{}.
+func (d *Dynago) unmarshalSet(v reflect.Value, av *dynamodb.AttributeValue, layout string) error {
+	if av == nil {
+		return nil
+	}
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	if av.M != nil && isSetMap(v.Type()) {
+		// Maps written before the field had a set type.
+		return d.simpleUnmarshal(v, av, layout)
+	}
+	var els []*dynamodb.AttributeValue
+	for _, s := range av.SS {
+		els = append(els, &dynamodb.AttributeValue{S: s})
+	}
+	for _, n := range av.NS {
+		els = append(els, &dynamodb.AttributeValue{N: n})
+	}
+	for _, b := range av.BS {
+		els = append(els, &dynamodb.AttributeValue{B: b})
+	}
+	if len(els) == 0 && av.L != nil {
+		els = av.L
+	}
+	switch {
+	case v.Kind() == reflect.Slice:
+		sl := reflect.MakeSlice(v.Type(), len(els), len(els))
+		for i, el := range els {
+			if err := d.simpleUnmarshal(sl.Index(i), el, layout); err != nil {
+				return err
+			}
+		}
+		v.Set(sl)
+	case isSetMap(v.Type()):
+		m := reflect.MakeMapWithSize(v.Type(), len(els))
+		for _, el := range els {
+			key := reflect.New(v.Type().Key()).Elem()
+			if err := d.simpleUnmarshal(key, el, layout); err != nil {
+				return err
+			}
+			m.SetMapIndex(key, reflect.Zero(emptyStructType))
+		}
+		v.Set(m)
+	default:
+		return fmt.Errorf("dynago: set can not be unmarshalled into %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package dynago
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/twharmon/dynago/internal/tags"
+)
+
+// template is a fmt tag, as parsed by tags.ParseTemplate, with the
+// fields of its placeholders.
+type template struct {
+	*tags.Template
+
+	// fields holds the field of each placeholder, or nil for literals.
+	fields []*field
+}
+
+// format formats the struct value v with the template.
+func (t *template) format(v reflect.Value) (string, error) {
+	var b strings.Builder
+	for i, p := range t.Parts {
+		if !p.Placeholder {
+			b.WriteString(p.Literal)
+			continue
+		}
+		f := t.fields[i]
+		s, err := formatValue(f.value(v, false), f.layout)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(t.Value(i, s))
+	}
+	return b.String(), nil
+}
+
+// parse parses s with the template and sets the fields of the struct
+// value v. False is returned if s does not match the template, in
+// which case no fields are set.
+func (t *template) parse(s string, v reflect.Value) (bool, error) {
+	values, ok := t.Scan(s)
+	if !ok {
+		return false, nil
+	}
+	for i, p := range t.Parts {
+		if !p.Placeholder {
+			continue
+		}
+		f := t.fields[i]
+		fv := f.value(v, true)
+		if !fv.IsValid() {
+			continue
+		}
+		if err := parseValue(fv, p.Trim(values[i]), f.layout); err != nil {
+			return false, fmt.Errorf("field %s: %w", f.name, err)
+		}
+	}
+	return true, nil
+}
+
+// FmtValue pads the formatted value s of a placeholder of a fmt tag
+// to the width, with zeros if zero is true, and escapes it for the
+// literal next following it. It is used by the methods generated by
+// cmd/dynago-gen.
+func FmtValue(s string, width int, zero bool, next string) string {
+	return tags.Escape(tags.Pad(s, width, zero), next)
+}
+
+// ScanFmt splits the attribute value s into the unescaped values of
+// the parts of a fmt tag, given by their literals and whether they are
+// placeholders. False is returned if s does not match the tag. It is
+// used by the methods generated by cmd/dynago-gen.
+func ScanFmt(s string, literals []string, placeholders []bool) ([]string, bool) {
+	return tags.Scan(s, literals, placeholders)
+}
+
+// formatValue formats a value substituted in a fmt template. Nil
+// pointers are formatted as an empty string.
+func formatValue(fval reflect.Value, layout string) (string, error) {
+	for fval.Kind() == reflect.Pointer {
+		fval = fval.Elem()
+	}
+	if !fval.IsValid() {
+		return "", nil
+	}
+	if fval.Type() != timeType && fval.CanInterface() {
+		if m, ok := fval.Interface().(encoding.TextMarshaler); ok {
+			b, err := m.MarshalText()
+			if err != nil {
+				return "", fmt.Errorf("MarshalText: %w", err)
+			}
+			return string(b), nil
+		}
+		if fval.CanAddr() {
+			if m, ok := fval.Addr().Interface().(encoding.TextMarshaler); ok {
+				b, err := m.MarshalText()
+				if err != nil {
+					return "", fmt.Errorf("MarshalText: %w", err)
+				}
+				return string(b), nil
+			}
+		}
+	}
+	switch fval.Kind() {
+	case reflect.String:
+		return fval.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(fval.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(fval.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(fval.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(fval.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(fval.Float(), 'f', -1, 64), nil
+	case reflect.Struct:
+		if fval.Type() == timeType {
+			return tags.FormatTime(fval.Interface().(time.Time), layout), nil
+		}
+	}
+	return "", fmt.Errorf("dynago: can not format value of type %s", fval.Type())
+}
+
+// parseValue parses a value substituted in a fmt template into fval.
+func parseValue(fval reflect.Value, str string, layout string) error {
+	for fval.Kind() == reflect.Pointer {
+		if fval.IsNil() {
+			fval.Set(reflect.New(fval.Type().Elem()))
+		}
+		fval = fval.Elem()
+	}
+	fty := fval.Type()
+	if fty != timeType && fval.CanAddr() {
+		if u, ok := fval.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			if err := u.UnmarshalText([]byte(str)); err != nil {
+				return fmt.Errorf("UnmarshalText: %w", err)
+			}
+			return nil
+		}
+	}
+	switch fty.Kind() {
+	case reflect.String:
+		fval.SetString(str)
+	case reflect.Bool:
+		val, err := strconv.ParseBool(str)
+		if err != nil {
+			return err
+		}
+		fval.SetBool(val)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		val, err := strconv.ParseInt(str, 10, fty.Bits())
+		if err != nil {
+			return err
+		}
+		fval.SetInt(val)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		val, err := strconv.ParseUint(str, 10, fty.Bits())
+		if err != nil {
+			return err
+		}
+		fval.SetUint(val)
+	case reflect.Float32, reflect.Float64:
+		val, err := strconv.ParseFloat(str, fty.Bits())
+		if err != nil {
+			return err
+		}
+		fval.SetFloat(val)
+	case reflect.Struct:
+		if fty == timeType {
+			t, err := tags.ParseTime(str, layout)
+			if err != nil {
+				return fmt.Errorf("parseTime: %w", err)
+			}
+			fval.Set(reflect.ValueOf(t))
+		}
+	}
+	return nil
+}
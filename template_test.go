@@ -0,0 +1,178 @@
+package dynago_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/twharmon/dynago"
+)
+
+func TestTemplateMultiplePlaceholders(t *testing.T) {
+	type Member struct {
+		OrgID  string `attr:"-"`
+		UserID string `attr:"PK" fmt:"Org#{OrgID}#User#{}"`
+	}
+	m := Member{OrgID: "a#b\\c", UserID: "d#e"}
+	client := dynago.New(nil)
+	got, err := client.Marshal(&m)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, map[string]*dynamodb.AttributeValue{
+		"PK": {S: aws.String(`Org#a\#b\\c#User#d#e`)},
+	}, got)
+	var back Member
+	if err := client.Unmarshal(got, &back); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, m, back)
+}
+
+func TestTemplateUnescapedValues(t *testing.T) {
+	type Member struct {
+		OrgID  string `attr:"-"`
+		UserID string `attr:"PK" fmt:"Org#{OrgID}#User#{}"`
+	}
+	client := dynago.New(nil)
+	var got Member
+	if err := client.Unmarshal(map[string]*dynamodb.AttributeValue{
+		"PK": {S: aws.String("Org#a#b#User#c")},
+	}, &got); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, Member{OrgID: "a#b", UserID: "c"}, got)
+}
+
+func TestTemplateMismatch(t *testing.T) {
+	type Member struct {
+		OrgID  string `attr:"-"`
+		UserID string `attr:"PK" fmt:"Org#{OrgID}#User#{}"`
+	}
+	client := dynago.New(nil)
+	var got Member
+	if err := client.Unmarshal(map[string]*dynamodb.AttributeValue{
+		"PK": {S: aws.String("Team#a")},
+	}, &got); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, Member{}, got)
+}
+
+func TestTemplatePadding(t *testing.T) {
+	type Score struct {
+		Player string `attr:"PK"`
+		Points int64  `attr:"SK" fmt:"Points#{:010d}"`
+		Rank   int    `fmt:"{:4}"`
+		Delta  int    `fmt:"{:05d}"`
+	}
+	s := Score{Player: "foo", Points: 4200, Rank: 7, Delta: -12}
+	client := dynago.New(nil)
+	got, err := client.Marshal(&s)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, map[string]*dynamodb.AttributeValue{
+		"PK":    {S: aws.String("foo")},
+		"SK":    {S: aws.String("Points#0000004200")},
+		"Rank":  {S: aws.String("   7")},
+		"Delta": {S: aws.String("-0012")},
+	}, got)
+	var back Score
+	if err := client.Unmarshal(got, &back); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, s, back)
+}
+
+func TestTemplateBoolAndTime(t *testing.T) {
+	type Event struct {
+		Active bool      `attr:"-"`
+		At     time.Time `attr:"-" layout:"unix"`
+		ID     string    `attr:"PK" fmt:"{{{Active}}}#{At:012d}#{}"`
+	}
+	e := Event{Active: true, At: time.Unix(1680674828, 0).UTC(), ID: "foo"}
+	client := dynago.New(nil)
+	got, err := client.Marshal(&e)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, map[string]*dynamodb.AttributeValue{
+		"PK": {S: aws.String("{true}#001680674828#foo")},
+	}, got)
+	var back Event
+	if err := client.Unmarshal(got, &back); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, e, back)
+}
+
+func TestTemplateInvalid(t *testing.T) {
+	client := dynago.New(nil)
+	for name, v := range map[string]interface{}{
+		"unclosed": &struct {
+			ID string `fmt:"Person#{"`
+		}{},
+		"unexpected": &struct {
+			ID string `fmt:"Person}#{}"`
+		}{},
+		"adjacent": &struct {
+			ID   string `fmt:"{Name}{}"`
+			Name string
+		}{},
+		"unknown field": &struct {
+			ID string `fmt:"{Name}#{}"`
+		}{},
+		"bad spec": &struct {
+			ID string `fmt:"{:x}"`
+		}{},
+		"d verb on string": &struct {
+			ID string `fmt:"{:05d}"`
+		}{},
+		"zero on string": &struct {
+			ID string `fmt:"c{:05}"`
+		}{},
+		"zero on time": &struct {
+			ID time.Time `fmt:"c{:030}"`
+		}{},
+		"unformattable": &struct {
+			ID   string `fmt:"{Tags}#{}"`
+			Tags []string
+		}{},
+	} {
+		if _, err := client.Marshal(v); err == nil {
+			t.Fatalf("%s: expected err", name)
+		}
+	}
+}
+
+func TestTemplateKeyConditionPadding(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Score struct {
+		*CompositeTable
+		Player string `attr:"PK" fmt:"Player#{}"`
+		Points int64  `attr:"SK" fmt:"Points#{:06d}"`
+	}
+	ddb.MockQuery(&dynamodb.QueryInput{
+		TableName:              aws.String("foo"),
+		KeyConditionExpression: aws.String("#k0 = :k0 AND #k1 >= :k1"),
+		ExpressionAttributeNames: map[string]*string{
+			"#k0": aws.String("PK"),
+			"#k1": aws.String("SK"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":k0": {S: aws.String("Player#foo")},
+			":k1": {S: aws.String("Points#000100")},
+		},
+		ConsistentRead: aws.Bool(false),
+	}, &dynamodb.QueryOutput{})
+	var got []*Score
+	if err := client.Query(&got).
+		KeyCondition(dynago.Key("PK").Equal("foo").And(dynago.Key("SK").GreaterThanEqual(100))).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	ddb.done()
+}
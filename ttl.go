@@ -0,0 +1,74 @@
+package dynago
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// ttlField returns the field of the struct type ty with the TTL tag,
+// or nil if there is none.
+func (d *Dynago) ttlField(ty reflect.Type) (*field, error) {
+	if ty == nil || ty.Kind() != reflect.Struct {
+		return nil, nil
+	}
+	c, err := d.codec(ty)
+	if err != nil {
+		return nil, fmt.Errorf("d.codec: %w", err)
+	}
+	return c.ttl, nil
+}
+
+// ttlAttrName returns the name of the TTL attribute of items of the
+// struct type ty.
+func (d *Dynago) ttlAttrName(ty reflect.Type) (string, error) {
+	f, err := d.ttlField(ty)
+	if err != nil {
+		return "", err
+	}
+	if f != nil {
+		return f.attrName, nil
+	}
+	return d.config.TTLAttrName, nil
+}
+
+// expired reports whether the TTL attribute of the item has passed. It
+// is false if the item has no TTL attribute, or if it is not a number.
+func (d *Dynago) expired(ty reflect.Type, item map[string]*dynamodb.AttributeValue) (bool, error) {
+	name, err := d.ttlAttrName(ty)
+	if err != nil {
+		return false, err
+	}
+	av := item[name]
+	if av == nil || av.N == nil {
+		return false, nil
+	}
+	n, err := strconv.ParseInt(*av.N, 10, 64)
+	if err != nil {
+		return false, nil
+	}
+	return n <= d.config.Now().Unix(), nil
+}
+
+// expiredFilter returns filter combined with a condition that the TTL
+// attribute of items of the struct type ty has not passed. Items with
+// no TTL attribute, or whose TTL attribute is not a number, pass it.
+func (d *Dynago) expiredFilter(ty reflect.Type, filter *string, names *map[string]*string, values *map[string]*dynamodb.AttributeValue) (*string, error) {
+	name, err := d.ttlAttrName(ty)
+	if err != nil {
+		return nil, err
+	}
+	*names = copyNames(*names)
+	*values = copyValues(*values)
+	return d.condition(filter, Not(Attr(name).LessThanEqual(d.config.Now().Unix())), "t", nil, names, values)
+}
+
+// expiry returns the TTL attribute value of an item that expires after
+// the duration.
+func (d *Dynago) expiry(ttl time.Duration) *dynamodb.AttributeValue {
+	n := strconv.FormatInt(d.config.Now().Add(ttl).Unix(), 10)
+	return &dynamodb.AttributeValue{N: &n}
+}
@@ -0,0 +1,229 @@
+package dynago_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/twharmon/dynago"
+)
+
+type Session struct {
+	*SimpleTable
+	ID      string    `attr:"PK" fmt:"Session#{}"`
+	Expires time.Time `ttl:""`
+}
+
+func TestTTLMarshal(t *testing.T) {
+	client := dynago.New(nil)
+	s := Session{ID: "foo", Expires: time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC)}
+	got, err := client.Marshal(&s)
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, map[string]*dynamodb.AttributeValue{
+		"PK":      {S: aws.String("Session#foo")},
+		"Expires": {N: aws.String("1680674828")},
+	}, got)
+}
+
+func TestTTLMarshalZero(t *testing.T) {
+	client := dynago.New(nil)
+	got, err := client.Marshal(&Session{ID: "foo"})
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, map[string]*dynamodb.AttributeValue{
+		"PK": {S: aws.String("Session#foo")},
+	}, got)
+}
+
+func TestTTLUnmarshal(t *testing.T) {
+	client := dynago.New(nil)
+	var got Session
+	if err := client.Unmarshal(map[string]*dynamodb.AttributeValue{
+		"PK":      {S: aws.String("Session#foo")},
+		"Expires": {N: aws.String("1680674828")},
+	}, &got); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, Session{ID: "foo", Expires: time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC)}, got)
+}
+
+func TestTTLInvalidField(t *testing.T) {
+	client := dynago.New(nil)
+	type Invalid struct {
+		*SimpleTable
+		ID      string `attr:"PK"`
+		Expires int64  `ttl:""`
+	}
+	if _, err := client.Marshal(&Invalid{ID: "foo"}); err == nil {
+		t.Fatalf("expected err")
+	}
+}
+
+func TestTTLPutItem(t *testing.T) {
+	ddb := mock(t)
+	now := time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC)
+	client := dynago.New(ddb, &dynago.Config{
+		DefaultTableName: "foo",
+		Now:              func() time.Time { return now },
+	})
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+	}
+	ddb.MockPut(&dynamodb.PutItemInput{
+		TableName: aws.String("foo"),
+		Item: map[string]*dynamodb.AttributeValue{
+			"PK":  {S: aws.String("Person#foo")},
+			"TTL": {N: aws.String("1680678428")},
+		},
+	})
+	if err := client.PutItem(&Person{Name: "foo"}).TTL(time.Hour).Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	ddb.done()
+}
+
+func TestTTLPutItemField(t *testing.T) {
+	ddb := mock(t)
+	now := time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC)
+	client := dynago.New(ddb, &dynago.Config{
+		DefaultTableName: "foo",
+		Now:              func() time.Time { return now },
+	})
+	ddb.MockPut(&dynamodb.PutItemInput{
+		TableName: aws.String("foo"),
+		Item: map[string]*dynamodb.AttributeValue{
+			"PK":      {S: aws.String("Session#foo")},
+			"Expires": {N: aws.String("1680678428")},
+		},
+	})
+	s := Session{ID: "foo", Expires: now.Add(time.Minute)}
+	if err := client.PutItem(&s).TTL(time.Hour).Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	ddb.done()
+}
+
+func TestTTLGetItemExpired(t *testing.T) {
+	ddb := mock(t)
+	now := time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC)
+	client := dynago.New(ddb, &dynago.Config{
+		DefaultTableName: "foo",
+		Now:              func() time.Time { return now },
+	})
+	ddb.MockGet(&dynamodb.GetItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Session#foo")},
+		},
+		TableName:      aws.String("foo"),
+		ConsistentRead: aws.Bool(false),
+	}, &dynamodb.GetItemOutput{
+		Item: map[string]*dynamodb.AttributeValue{
+			"PK":      {S: aws.String("Session#foo")},
+			"Expires": {N: aws.String("1680674828")},
+		},
+	})
+	s := Session{ID: "foo"}
+	if err := client.GetItem(&s).FilterExpired().Exec(); !errors.Is(err, dynago.ErrItemNotFound) {
+		t.Fatalf("expected ErrItemNotFound; got %v", err)
+	}
+	ddb.done()
+}
+
+func TestTTLGetItemNotExpired(t *testing.T) {
+	ddb := mock(t)
+	now := time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC)
+	client := dynago.New(ddb, &dynago.Config{
+		DefaultTableName: "foo",
+		Now:              func() time.Time { return now },
+	})
+	ddb.MockGet(&dynamodb.GetItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Session#foo")},
+		},
+		TableName:      aws.String("foo"),
+		ConsistentRead: aws.Bool(false),
+	}, &dynamodb.GetItemOutput{
+		Item: map[string]*dynamodb.AttributeValue{
+			"PK":      {S: aws.String("Session#foo")},
+			"Expires": {N: aws.String("1680674829")},
+		},
+	})
+	s := Session{ID: "foo"}
+	if err := client.GetItem(&s).FilterExpired().Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, now.Add(time.Second), s.Expires)
+	ddb.done()
+}
+
+func TestTTLQueryFilterExpired(t *testing.T) {
+	ddb := mock(t)
+	now := time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC)
+	client := dynago.New(ddb, &dynago.Config{
+		DefaultTableName: "foo",
+		Now:              func() time.Time { return now },
+	})
+	ddb.MockQuery(&dynamodb.QueryInput{
+		TableName:              aws.String("foo"),
+		KeyConditionExpression: aws.String("#k0 = :k0"),
+		FilterExpression:       aws.String("NOT (#t0 <= :t0)"),
+		ExpressionAttributeNames: map[string]*string{
+			"#k0": aws.String("PK"),
+			"#t0": aws.String("Expires"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":k0": {S: aws.String("Session#foo")},
+			":t0": {N: aws.String("1680674828")},
+		},
+		ConsistentRead: aws.Bool(false),
+	}, &dynamodb.QueryOutput{})
+	var got []*Session
+	if err := client.Query(&got).
+		KeyCondition(dynago.Key("PK").Equal("foo")).
+		FilterExpired().
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	ddb.done()
+}
+
+func TestTTLScanFilterExpired(t *testing.T) {
+	ddb := mock(t)
+	now := time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC)
+	client := dynago.New(ddb, &dynago.Config{
+		DefaultTableName: "foo",
+		Now:              func() time.Time { return now },
+	})
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64
+	}
+	ddb.MockScan(&dynamodb.ScanInput{
+		TableName:        aws.String("foo"),
+		FilterExpression: aws.String("(#f0 > :f0) AND (NOT (#t0 <= :t0))"),
+		ExpressionAttributeNames: map[string]*string{
+			"#f0": aws.String("Age"),
+			"#t0": aws.String("TTL"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":f0": {N: aws.String("30")},
+			":t0": {N: aws.String("1680674828")},
+		},
+		ConsistentRead: aws.Bool(false),
+	}, &dynamodb.ScanOutput{})
+	var got []*Person
+	if err := client.Scan(&got).
+		Filter(dynago.Attr("Age").GreaterThan(30)).
+		FilterExpired().
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	ddb.done()
+}
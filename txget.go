@@ -0,0 +1,100 @@
+package dynago
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// TransactionGetItemer interface is to provide a
+// dynamodb.TransactGetItem and the item the result is unmarshalled
+// into.
+type TransactionGetItemer interface {
+	TransactionGetItem() (*dynamodb.TransactGetItem, Keyer, error)
+}
+
+// TransactionGetItems represents a TransactGetItems operation.
+type TransactionGetItems struct {
+	input  *dynamodb.TransactGetItemsInput
+	items  []TransactionGetItemer
+	output *TransactionGetItemsOutput
+	client *Dynago
+}
+
+// TransactionGetItemsOutput represents the output of a
+// TransactionGetItems operation.
+type TransactionGetItemsOutput struct {
+	// NotFound holds the items that were not found.
+	NotFound []Keyer
+
+	// ConsumedCapacity holds the capacity consumed by each table, if
+	// ReturnConsumedCapacity was set.
+	ConsumedCapacity []*dynamodb.ConsumedCapacity
+}
+
+// TransactionGetItems returns a TransactionGetItems operation.
+func (d *Dynago) TransactionGetItems() *TransactionGetItems {
+	var i TransactionGetItems
+	i.input = &dynamodb.TransactGetItemsInput{}
+	i.client = d
+	return &i
+}
+
+// Items adds items to the transaction.
+func (i *TransactionGetItems) Items(items ...TransactionGetItemer) *TransactionGetItems {
+	i.items = append(i.items, items...)
+	return i
+}
+
+// ReturnConsumedCapacity sets ReturnConsumedCapacity.
+func (i *TransactionGetItems) ReturnConsumedCapacity(val string) *TransactionGetItems {
+	i.input.ReturnConsumedCapacity = &val
+	return i
+}
+
+// Output sets the output to be populated when the operation is
+// executed.
+func (i *TransactionGetItems) Output(output *TransactionGetItemsOutput) *TransactionGetItems {
+	i.output = output
+	return i
+}
+
+// Exec executes the operation. Items that are found are unmarshalled
+// into the structs of the GetItem operations.
+func (i *TransactionGetItems) Exec() error {
+	return i.ExecWithContext(context.Background())
+}
+
+// ExecWithContext executes the operation with the given context.
+func (i *TransactionGetItems) ExecWithContext(ctx context.Context) error {
+	i.input.TransactItems = nil
+	keyers := make([]Keyer, len(i.items))
+	for j, item := range i.items {
+		txitem, keyer, err := item.TransactionGetItem()
+		if err != nil {
+			return err
+		}
+		keyers[j] = keyer
+		i.input.TransactItems = append(i.input.TransactItems, txitem)
+	}
+	output, err := i.client.ddb.TransactGetItemsWithContext(ctx, i.input)
+	if err != nil {
+		return fmt.Errorf("d.ddb.TransactGetItems: %w", mapError(err))
+	}
+	var notFound []Keyer
+	for j, keyer := range keyers {
+		if j >= len(output.Responses) || len(output.Responses[j].Item) == 0 {
+			notFound = append(notFound, keyer)
+			continue
+		}
+		if err := i.client.Unmarshal(output.Responses[j].Item, keyer); err != nil {
+			return fmt.Errorf("i.client.Unmarshal: %w", err)
+		}
+	}
+	if i.output != nil {
+		i.output.NotFound = notFound
+		i.output.ConsumedCapacity = output.ConsumedCapacity
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package dynago_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/twharmon/dynago"
+)
+
+func TestTransactGetItemsBasic(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64
+	}
+	type Post struct {
+		*CompositeTable
+		Author string `attr:"PK" fmt:"Author#{}"`
+		ID     int64  `attr:"SK" fmt:"Post#{}"`
+		Title  string
+	}
+	tableName := "bar"
+	ddb.MockTransactGetItems(&dynamodb.TransactGetItemsInput{
+		TransactItems: []*dynamodb.TransactGetItem{
+			{
+				Get: &dynamodb.Get{
+					Key: map[string]*dynamodb.AttributeValue{
+						"PK": {S: aws.String("Person#foo")},
+					},
+					TableName: &tableName,
+				},
+			},
+			{
+				Get: &dynamodb.Get{
+					Key: map[string]*dynamodb.AttributeValue{
+						"PK": {S: aws.String("Author#foo")},
+						"SK": {S: aws.String("Post#1")},
+					},
+					TableName:            &tableName,
+					ProjectionExpression: aws.String("#t"),
+					ExpressionAttributeNames: map[string]*string{
+						"#t": aws.String("Title"),
+					},
+				},
+			},
+		},
+	}, &dynamodb.TransactGetItemsOutput{
+		Responses: []*dynamodb.ItemResponse{
+			{Item: map[string]*dynamodb.AttributeValue{
+				"PK":  {S: aws.String("Person#foo")},
+				"Age": {N: aws.String("33")},
+			}},
+			{Item: map[string]*dynamodb.AttributeValue{
+				"Title": {S: aws.String("bar")},
+			}},
+		},
+	})
+	person := Person{Name: "foo"}
+	post := Post{Author: "foo", ID: 1}
+	var output dynago.TransactionGetItemsOutput
+	if err := client.TransactionGetItems().
+		Items(
+			client.GetItem(&person).TableName(tableName),
+			client.GetItem(&post).
+				TableName(tableName).
+				ProjectionExpression("#t").
+				ExpressionAttributeName("#t", "Title"),
+		).
+		Output(&output).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, Person{Name: "foo", Age: 33}, person)
+	assertEq(t, Post{Author: "foo", ID: 1, Title: "bar"}, post)
+	assertEq(t, []dynago.Keyer(nil), output.NotFound)
+	ddb.done()
+}
+
+func TestTransactGetItemsNotFound(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64
+	}
+	tableName := "bar"
+	ddb.MockTransactGetItems(&dynamodb.TransactGetItemsInput{
+		TransactItems: []*dynamodb.TransactGetItem{
+			{
+				Get: &dynamodb.Get{
+					Key: map[string]*dynamodb.AttributeValue{
+						"PK": {S: aws.String("Person#foo")},
+					},
+					TableName: &tableName,
+				},
+			},
+			{
+				Get: &dynamodb.Get{
+					Key: map[string]*dynamodb.AttributeValue{
+						"PK": {S: aws.String("Person#bar")},
+					},
+					TableName: &tableName,
+				},
+			},
+		},
+	}, &dynamodb.TransactGetItemsOutput{
+		Responses: []*dynamodb.ItemResponse{
+			{},
+			{Item: map[string]*dynamodb.AttributeValue{
+				"PK":  {S: aws.String("Person#bar")},
+				"Age": {N: aws.String("34")},
+			}},
+		},
+	})
+	foo := Person{Name: "foo"}
+	bar := Person{Name: "bar"}
+	var output dynago.TransactionGetItemsOutput
+	if err := client.TransactionGetItems().
+		Items(
+			client.GetItem(&foo).TableName(tableName),
+			client.GetItem(&bar).TableName(tableName),
+		).
+		Output(&output).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, []dynago.Keyer{&foo}, output.NotFound)
+	assertEq(t, Person{Name: "bar", Age: 34}, bar)
+	ddb.done()
+}
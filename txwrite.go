@@ -1,6 +1,9 @@
 package dynago
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 )
 
@@ -14,9 +17,16 @@ type TransactionWriteItemer interface {
 type TransactionWriteItems struct {
 	input  *dynamodb.TransactWriteItemsInput
 	items  []TransactionWriteItemer
+	output *TransactionWriteItemsOutput
 	client *Dynago
 }
 
+// TransactionWriteItemsOutput represents the output of a
+// TransactionWriteItems operation.
+type TransactionWriteItemsOutput struct {
+	ConsumedCapacity []*dynamodb.ConsumedCapacity
+}
+
 // TransactionWriteItems returns a TransactionWriteItems operation.
 func (d *Dynago) TransactionWriteItems() *TransactionWriteItems {
 	var i TransactionWriteItems
@@ -37,8 +47,27 @@ func (i *TransactionWriteItems) ClientRequestToken(token string) *TransactionWri
 	return i
 }
 
+// ReturnConsumedCapacity sets ReturnConsumedCapacity.
+func (i *TransactionWriteItems) ReturnConsumedCapacity(val string) *TransactionWriteItems {
+	i.input.ReturnConsumedCapacity = &val
+	return i
+}
+
+// Output sets the output to be populated when the operation is
+// executed.
+func (i *TransactionWriteItems) Output(output *TransactionWriteItemsOutput) *TransactionWriteItems {
+	i.output = output
+	return i
+}
+
 // Exec executes the operation.
 func (i *TransactionWriteItems) Exec() error {
+	return i.ExecWithContext(context.Background())
+}
+
+// ExecWithContext executes the operation with the given context.
+func (i *TransactionWriteItems) ExecWithContext(ctx context.Context) error {
+	i.input.TransactItems = nil
 	for _, item := range i.items {
 		txitem, err := item.TransactionWriteItem()
 		if err != nil {
@@ -46,6 +75,17 @@ func (i *TransactionWriteItems) Exec() error {
 		}
 		i.input.TransactItems = append(i.input.TransactItems, txitem)
 	}
-	_, err := i.client.ddb.TransactWriteItems(i.input)
-	return err
+	output, err := i.client.ddb.TransactWriteItemsWithContext(ctx, i.input)
+	if err != nil {
+		return fmt.Errorf("d.ddb.TransactWriteItems: %w", transactionCanceledError(err, i.items))
+	}
+	if i.output != nil {
+		i.output.ConsumedCapacity = output.ConsumedCapacity
+	}
+	for _, item := range i.items {
+		if v, ok := item.(versioner); ok {
+			v.commit()
+		}
+	}
+	return nil
 }
@@ -140,3 +140,38 @@ func TestTransactWriteItemsClientReqTok(t *testing.T) {
 	}
 	ddb.done()
 }
+
+func TestTransactWriteItemsReturnConsumedCapacity(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		*CompositeTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+	}
+	p := Person{Name: "foo"}
+	tableName := "bar"
+	cc := []*dynamodb.ConsumedCapacity{{TableName: &tableName, CapacityUnits: aws.Float64(2)}}
+	ddb.MockTransactWriteItems(&dynamodb.TransactWriteItemsInput{
+		ReturnConsumedCapacity: aws.String(dynamodb.ReturnConsumedCapacityTotal),
+		TransactItems: []*dynamodb.TransactWriteItem{
+			{
+				Put: &dynamodb.Put{
+					Item: map[string]*dynamodb.AttributeValue{
+						"PK": {S: aws.String("Person#foo")},
+					},
+					TableName: &tableName,
+				},
+			},
+		},
+	}, &dynamodb.TransactWriteItemsOutput{ConsumedCapacity: cc})
+	var output dynago.TransactionWriteItemsOutput
+	if err := client.TransactionWriteItems().
+		Items(client.PutItem(&p).TableName(tableName)).
+		ReturnConsumedCapacity(dynamodb.ReturnConsumedCapacityTotal).
+		Output(&output).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, cc, output.ConsumedCapacity)
+	ddb.done()
+}
@@ -0,0 +1,186 @@
+package dynago
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// updateClauses holds the actions of an UpdateExpression built with
+// the Set, SetIfNotExists, Increment, ListAppend, Remove, Add and
+// Delete methods of UpdateItem.
+type updateClauses struct {
+	exp    *expression
+	set    []string
+	remove []string
+	add    []string
+	del    []string
+}
+
+// String returns the UpdateExpression.
+func (u *updateClauses) String() string {
+	var parts []string
+	for _, clause := range []struct {
+		name    string
+		actions []string
+	}{
+		{"SET", u.set},
+		{"REMOVE", u.remove},
+		{"ADD", u.add},
+		{"DELETE", u.del},
+	} {
+		if len(clause.actions) > 0 {
+			parts = append(parts, clause.name+" "+strings.Join(clause.actions, ", "))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// update adds an action built by fn to the given clause, and sets the
+// UpdateExpression from all of the actions.
+func (q *UpdateItem) update(clause string, fn func(e *expression) (string, error)) *UpdateItem {
+	if q.clauses == nil {
+		q.clauses = &updateClauses{
+			exp: q.dynago.expression("u", itemType(q.item), &q.input.ExpressionAttributeNames, &q.input.ExpressionAttributeValues),
+		}
+	}
+	action, err := fn(q.clauses.exp)
+	if err != nil {
+		q.err = fmt.Errorf("dynago: UpdateItem: %w", err)
+		return q
+	}
+	switch clause {
+	case "SET":
+		q.clauses.set = append(q.clauses.set, action)
+	case "REMOVE":
+		q.clauses.remove = append(q.clauses.remove, action)
+	case "ADD":
+		q.clauses.add = append(q.clauses.add, action)
+	case "DELETE":
+		q.clauses.del = append(q.clauses.del, action)
+	}
+	exp := q.clauses.String()
+	q.input.UpdateExpression = &exp
+	return q
+}
+
+// pathValue returns the placeholders of a document path and of a
+// value of the attribute at that path.
+func pathValue(e *expression, path string, v interface{}) (string, string, error) {
+	p, err := e.path(path)
+	if err != nil {
+		return "", "", err
+	}
+	attrName, err := e.dynago.attrPath(e.ty, path)
+	if err != nil {
+		return "", "", err
+	}
+	if strings.ContainsAny(attrName, ".[") {
+		attrName = ""
+	}
+	val, err := e.value(attrName, v, false)
+	if err != nil {
+		return "", "", fmt.Errorf("attribute %s: %w", path, err)
+	}
+	return p, val, nil
+}
+
+// setAttrValue marshals a slice or a map[T]struct{} into a string,
+// number or binary set. Other values, such as numbers to ADD, are
+// marshalled as is.
+func (d *Dynago) setAttrValue(v interface{}) (*dynamodb.AttributeValue, error) {
+	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Pointer {
+		val = val.Elem()
+	}
+	var elTy reflect.Type
+	switch {
+	case val.Kind() == reflect.Slice && val.Type().Elem().Kind() != reflect.Uint8:
+		elTy = val.Type().Elem()
+	case isSetMap(val.Type()):
+		elTy = val.Type().Key()
+	default:
+		return d.simpleMarshal(val, "")
+	}
+	ty := setType(elTy)
+	if ty == "" {
+		return nil, fmt.Errorf("dynago: elements of a set must all be strings, numbers or binary")
+	}
+	av, err := d.marshalSet(val, ty, "")
+	if err != nil {
+		return nil, err
+	}
+	if av == nil {
+		return nil, fmt.Errorf("dynago: sets must not be empty")
+	}
+	return av, nil
+}
+
+var setClauseRegExp = regexp.MustCompile(`(?i)(^|\s)SET\s+`)
+
+// addSetAction adds a SET action to an UpdateExpression.
+func addSetAction(exp *string, action string) *string {
+	if exp == nil || *exp == "" {
+		s := "SET " + action
+		return &s
+	}
+	if loc := setClauseRegExp.FindStringIndex(*exp); loc != nil {
+		s := (*exp)[:loc[1]] + action + ", " + (*exp)[loc[1]:]
+		return &s
+	}
+	s := "SET " + action + " " + *exp
+	return &s
+}
+
+var updateClauseRegExp = regexp.MustCompile(`(?i)(^|\s)(SET|REMOVE|ADD|DELETE)\s+`)
+
+// updatedAttrs returns the top level attributes that the actions of
+// an UpdateExpression write to. Placeholders are resolved with names.
+func updatedAttrs(exp *string, names map[string]*string) map[string]bool {
+	attrs := make(map[string]bool)
+	if exp == nil {
+		return attrs
+	}
+	locs := updateClauseRegExp.FindAllStringIndex(*exp, -1)
+	for i, loc := range locs {
+		end := len(*exp)
+		if i+1 < len(locs) {
+			end = locs[i+1][0]
+		}
+		for _, action := range splitActions((*exp)[loc[1]:end]) {
+			path := strings.TrimSpace(action)
+			if j := strings.IndexAny(path, " =.["); j >= 0 {
+				path = path[:j]
+			}
+			if name, ok := names[path]; ok && name != nil {
+				path = *name
+			}
+			attrs[path] = true
+		}
+	}
+	return attrs
+}
+
+// splitActions splits the actions of a clause of an UpdateExpression
+// on the commas outside of parentheses.
+func splitActions(clause string) []string {
+	var actions []string
+	depth, start := 0, 0
+	for i, r := range clause {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				actions = append(actions, clause[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(actions, clause[start:])
+}
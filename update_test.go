@@ -0,0 +1,210 @@
+package dynago_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/twharmon/dynago"
+)
+
+func TestUpdateBuilder(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Address struct {
+		City string `attr:"city"`
+	}
+	type Person struct {
+		*SimpleTable
+		Name    string `attr:"PK" fmt:"Person#{}"`
+		Team    string `attr:"team" fmt:"Team#{}"`
+		Address *Address
+		Tags    []string
+		Logins  int64
+	}
+	p := Person{Name: "foo"}
+	ddb.MockUpdate(&dynamodb.UpdateItemInput{
+		TableName: aws.String("foo"),
+		Key: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Person#foo")},
+		},
+		UpdateExpression: aws.String("SET #u0 = :u0, #u1.#u2 = if_not_exists(#u1.#u2, :u1), #u3 = if_not_exists(#u3, :u3) + :u2, #u4 = list_append(if_not_exists(#u4, :u4), :u5) REMOVE #u4[2], #u5 ADD #u6 :u6 DELETE #u7 :u7"),
+		ExpressionAttributeNames: map[string]*string{
+			"#u0": aws.String("team"),
+			"#u1": aws.String("Address"),
+			"#u2": aws.String("city"),
+			"#u3": aws.String("Logins"),
+			"#u4": aws.String("Tags"),
+			"#u5": aws.String("Old"),
+			"#u6": aws.String("Colors"),
+			"#u7": aws.String("Sizes"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":u0": {S: aws.String("Team#bar")},
+			":u1": {S: aws.String("Boston")},
+			":u2": {N: aws.String("1")},
+			":u3": {N: aws.String("0")},
+			":u4": {L: []*dynamodb.AttributeValue{}},
+			":u5": {L: []*dynamodb.AttributeValue{{S: aws.String("a")}, {S: aws.String("b")}}},
+			":u6": {SS: []*string{aws.String("red")}},
+			":u7": {NS: []*string{aws.String("1"), aws.String("2")}},
+		},
+	})
+	if err := client.UpdateItem(&p).
+		Set("Team", "bar").
+		SetIfNotExists("Address.City", "Boston").
+		Increment("Logins", 1).
+		ListAppend("Tags", "a", "b").
+		Remove("Tags[2]", "Old").
+		Add("Colors", []string{"red"}).
+		Delete("Sizes", []int{1, 2}).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	ddb.done()
+}
+
+func TestUpdateBuilderWithCondition(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64
+	}
+	p := Person{Name: "foo"}
+	ddb.MockUpdate(&dynamodb.UpdateItemInput{
+		TableName: aws.String("foo"),
+		Key: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Person#foo")},
+		},
+		UpdateExpression:    aws.String("REMOVE #u0"),
+		ConditionExpression: aws.String("attribute_exists(#c0)"),
+		ExpressionAttributeNames: map[string]*string{
+			"#u0": aws.String("Age"),
+			"#c0": aws.String("PK"),
+		},
+	})
+	if err := client.UpdateItem(&p).
+		Remove("Age").
+		Condition(dynago.Attr("Name").Exists()).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	ddb.done()
+}
+
+func TestUpdateBuilderEmptySet(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+	}
+	p := Person{Name: "foo"}
+	if err := client.UpdateItem(&p).Add("Colors", []string{}).Exec(); err == nil {
+		t.Fatalf("want err")
+	}
+	ddb.done()
+}
+
+func TestUpdateItemDiff(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Person struct {
+		*SimpleTable
+		Name  string `attr:"PK" fmt:"Person#{}"`
+		Email string `copy:"GSIPK"`
+		Score *int64
+		Age   int64
+	}
+	original := Person{Name: "foo", Email: "a@example.com", Score: aws.Int64(5), Age: 33}
+	modified := original
+	modified.Email = "b@example.com"
+	modified.Score = nil
+	ddb.MockUpdate(&dynamodb.UpdateItemInput{
+		TableName: aws.String("foo"),
+		Key: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Person#foo")},
+		},
+		UpdateExpression: aws.String("SET #u0 = :u0, #u1 = :u1 REMOVE #u2"),
+		ExpressionAttributeNames: map[string]*string{
+			"#u0": aws.String("Email"),
+			"#u1": aws.String("GSIPK"),
+			"#u2": aws.String("Score"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":u0": {S: aws.String("b@example.com")},
+			":u1": {S: aws.String("b@example.com")},
+		},
+	})
+	if err := client.UpdateItem(&modified).Diff(&original).Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	ddb.done()
+}
+
+func TestUpdateItemDiffUnchanged(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+		Age  int64
+	}
+	original := Person{Name: "foo", Age: 33}
+	modified := original
+	if err := client.UpdateItem(&modified).Diff(&original).Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	ddb.done()
+}
+
+func TestUpdateItemDiffKeyChanged(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Person struct {
+		*SimpleTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+	}
+	original := Person{Name: "foo"}
+	modified := Person{Name: "bar"}
+	if err := client.UpdateItem(&modified).Diff(&original).Exec(); err == nil {
+		t.Fatalf("want err")
+	}
+	ddb.done()
+}
+
+func TestUpdateBuilderSets(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Person struct {
+		*SimpleTable
+		Name  string `attr:"PK" fmt:"Person#{}"`
+		Tags  map[string]struct{}
+		Lucky []int64 `type:"NS"`
+	}
+	p := Person{Name: "foo"}
+	ddb.MockUpdate(&dynamodb.UpdateItemInput{
+		TableName: aws.String("foo"),
+		Key: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Person#foo")},
+		},
+		UpdateExpression: aws.String("SET #u1 = :u1 ADD #u0 :u0"),
+		ExpressionAttributeNames: map[string]*string{
+			"#u0": aws.String("Tags"),
+			"#u1": aws.String("Lucky"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":u0": {SS: []*string{aws.String("a"), aws.String("b")}},
+			":u1": {NS: []*string{aws.String("7")}},
+		},
+	})
+	if err := client.UpdateItem(&p).
+		Add("Tags", map[string]struct{}{"b": {}, "a": {}}).
+		Set("Lucky", []int64{7}).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	ddb.done()
+}
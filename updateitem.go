@@ -1,19 +1,35 @@
 package dynago
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/twharmon/slices"
 )
 
 // UpdateItem represents an UpdateItem operation.
 type UpdateItem struct {
-	item   Keyer
-	input  *dynamodb.UpdateItemInput
-	dynago *Dynago
-	err    error
+	item    Keyer
+	input   *dynamodb.UpdateItemInput
+	output  *UpdateItemOutput
+	values  interface{}
+	clauses *updateClauses
+	diff    bool
+	ver     *version
+	ts      *timestamps
+	dynago  *Dynago
+	err     error
+}
+
+// UpdateItemOutput represents the output of an UpdateItem operation.
+type UpdateItemOutput struct {
+	ConsumedCapacity *dynamodb.ConsumedCapacity
 }
 
 // UpdateItem returns an UpdateItem operation.
@@ -37,12 +53,177 @@ func (q *UpdateItem) UpdateExpression(exp string) *UpdateItem {
 	return q
 }
 
+// Set adds a SET action that sets the attribute at the given document
+// path to v. Struct field names in the path are resolved to attribute
+// names, and the fmt tag of a top level attribute is applied to v.
+// Actions replace an UpdateExpression set with UpdateExpression.
+func (q *UpdateItem) Set(path string, v interface{}) *UpdateItem {
+	return q.update("SET", func(e *expression) (string, error) {
+		p, val, err := pathValue(e, path, v)
+		return p + " = " + val, err
+	})
+}
+
+// SetIfNotExists adds a SET action that sets the attribute at the
+// given document path to v, unless the attribute exists.
+func (q *UpdateItem) SetIfNotExists(path string, v interface{}) *UpdateItem {
+	return q.update("SET", func(e *expression) (string, error) {
+		p, val, err := pathValue(e, path, v)
+		return fmt.Sprintf("%s = if_not_exists(%s, %s)", p, p, val), err
+	})
+}
+
+// Increment adds a SET action that adds n to the number attribute at
+// the given document path. The attribute is treated as 0 if it does
+// not exist. Use a negative n to decrement.
+func (q *UpdateItem) Increment(path string, n interface{}) *UpdateItem {
+	return q.update("SET", func(e *expression) (string, error) {
+		p, err := e.path(path)
+		if err != nil {
+			return "", err
+		}
+		val, err := e.value("", n, false)
+		if err != nil {
+			return "", err
+		}
+		zero := e.rawValue(&dynamodb.AttributeValue{N: aws.String("0")})
+		return fmt.Sprintf("%s = if_not_exists(%s, %s) + %s", p, p, zero, val), nil
+	})
+}
+
+// ListAppend adds a SET action that appends the values to the list
+// attribute at the given document path. The attribute is treated as
+// an empty list if it does not exist.
+func (q *UpdateItem) ListAppend(path string, values ...interface{}) *UpdateItem {
+	return q.update("SET", func(e *expression) (string, error) {
+		p, err := e.path(path)
+		if err != nil {
+			return "", err
+		}
+		list := &dynamodb.AttributeValue{L: make([]*dynamodb.AttributeValue, len(values))}
+		for i, v := range values {
+			av, err := q.dynago.simpleMarshal(reflect.ValueOf(v), time.RFC3339)
+			if err != nil {
+				return "", err
+			}
+			list.L[i] = av
+		}
+		empty := e.rawValue(&dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{}})
+		return fmt.Sprintf("%s = list_append(if_not_exists(%s, %s), %s)", p, p, empty, e.rawValue(list)), nil
+	})
+}
+
+// Remove adds a REMOVE action for each of the given document paths.
+func (q *UpdateItem) Remove(paths ...string) *UpdateItem {
+	for _, path := range paths {
+		q.update("REMOVE", func(e *expression) (string, error) {
+			return e.path(path)
+		})
+	}
+	return q
+}
+
+// Add adds an ADD action that adds v to the number or set attribute at
+// the given document path. Slices are added as string, number or
+// binary sets.
+func (q *UpdateItem) Add(path string, v interface{}) *UpdateItem {
+	return q.update("ADD", func(e *expression) (string, error) {
+		return q.setAction(e, path, v)
+	})
+}
+
+// Delete adds a DELETE action that deletes the elements of v from the
+// set attribute at the given document path.
+func (q *UpdateItem) Delete(path string, v interface{}) *UpdateItem {
+	return q.update("DELETE", func(e *expression) (string, error) {
+		return q.setAction(e, path, v)
+	})
+}
+
+func (q *UpdateItem) setAction(e *expression, path string, v interface{}) (string, error) {
+	p, err := e.path(path)
+	if err != nil {
+		return "", err
+	}
+	av, err := q.dynago.setAttrValue(v)
+	if err != nil {
+		return "", fmt.Errorf("attribute %s: %w", path, err)
+	}
+	return p + " " + e.rawValue(av), nil
+}
+
+// Diff adds SET actions for the attributes of the item that differ
+// from those of original, and REMOVE actions for the attributes of
+// original the item does not have. Both are marshalled with
+// Dynago.Marshal, so attributes copied with the copy tag and
+// additional attributes are compared too. Primary key attributes must
+// not differ. Exec does nothing if no attributes differ.
+func (q *UpdateItem) Diff(original Keyer) *UpdateItem {
+	q.diff = true
+	before, err := q.dynago.Marshal(original)
+	if err != nil {
+		q.err = fmt.Errorf("q.dynago.Marshal: %w", err)
+		return q
+	}
+	after, err := q.dynago.Marshal(q.item)
+	if err != nil {
+		q.err = fmt.Errorf("q.dynago.Marshal: %w", err)
+		return q
+	}
+	keys := q.item.PrimaryKeys()
+	for _, key := range keys {
+		if !reflect.DeepEqual(before[key], after[key]) {
+			q.err = fmt.Errorf("dynago: UpdateItem.Diff: primary key attribute %s differs", key)
+			return q
+		}
+	}
+	names := make([]string, 0, len(before)+len(after))
+	for name := range after {
+		names = append(names, name)
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if slices.Contains(keys, name) || reflect.DeepEqual(before[name], after[name]) {
+			continue
+		}
+		if av, ok := after[name]; ok {
+			q.update("SET", func(e *expression) (string, error) {
+				return e.name(name) + " = " + e.rawValue(av), nil
+			})
+		} else {
+			q.update("REMOVE", func(e *expression) (string, error) {
+				return e.name(name), nil
+			})
+		}
+	}
+	return q
+}
+
 // ConditionExpression sets the ConditionExpression.
 func (q *UpdateItem) ConditionExpression(exp string) *UpdateItem {
 	q.input.ConditionExpression = &exp
 	return q
 }
 
+// Condition sets the ConditionExpression from the given condition.
+// Placeholders for attribute names and values are generated and
+// added to ExpressionAttributeNames and ExpressionAttributeValues.
+// Conditions of repeated calls are combined with AND.
+func (q *UpdateItem) Condition(c *Condition) *UpdateItem {
+	exp, err := q.dynago.condition(q.input.ConditionExpression, c, "c", itemType(q.item), &q.input.ExpressionAttributeNames, &q.input.ExpressionAttributeValues)
+	if err != nil {
+		q.err = fmt.Errorf("dynago: UpdateItem.Condition: %w", err)
+		return q
+	}
+	q.input.ConditionExpression = exp
+	return q
+}
+
 // ExpressionAttributeValue sets an ExpressionAttributeValue.
 func (q *UpdateItem) ExpressionAttributeValue(key string, val interface{}, layout ...string) *UpdateItem {
 	if q.input.ExpressionAttributeValues == nil {
@@ -69,21 +250,123 @@ func (q *UpdateItem) ExpressionAttributeName(name string, sub string) *UpdateIte
 	return q
 }
 
+// ReturnValues sets ReturnValues. The returned attributes are
+// unmarshalled into the item, or into v if it is given. If old
+// attributes are unmarshalled into the item, its version and auto
+// timestamps are left as they were returned.
+func (q *UpdateItem) ReturnValues(val string, v ...interface{}) *UpdateItem {
+	q.input.ReturnValues = &val
+	q.values = q.item
+	if len(v) > 0 {
+		q.values = v[0]
+	}
+	return q
+}
+
+// ReturnConsumedCapacity sets ReturnConsumedCapacity.
+func (q *UpdateItem) ReturnConsumedCapacity(val string) *UpdateItem {
+	q.input.ReturnConsumedCapacity = &val
+	return q
+}
+
+// Output sets the output to be populated when the operation is
+// executed.
+func (q *UpdateItem) Output(output *UpdateItemOutput) *UpdateItem {
+	q.output = output
+	return q
+}
+
 // Exec executes the operation.
 func (q *UpdateItem) Exec() error {
+	return q.ExecWithContext(context.Background())
+}
+
+// ExecWithContext executes the operation with the given context.
+func (q *UpdateItem) ExecWithContext(ctx context.Context) error {
 	if q.err != nil {
 		return q.err
 	}
+	if q.diff && q.input.UpdateExpression == nil {
+		return nil
+	}
+	input, ver, ts, err := q.prepare()
+	if err != nil {
+		return err
+	}
+	output, err := q.dynago.ddb.UpdateItemWithContext(ctx, input)
+	if err != nil {
+		return fmt.Errorf("d.ddb.UpdateItem: %w", versionError(mapError(err), ver))
+	}
+	if q.output != nil {
+		q.output.ConsumedCapacity = output.ConsumedCapacity
+	}
+	if q.values != nil && len(output.Attributes) > 0 {
+		if err := q.dynago.Unmarshal(output.Attributes, q.values); err != nil {
+			return fmt.Errorf("q.dynago.Unmarshal: %w", err)
+		}
+		if q.values == interface{}(q.item) && returnsOld(input.ReturnValues) {
+			return nil
+		}
+	}
+	ts.commit()
+	if ver != nil {
+		ver.commit()
+	}
+	return nil
+}
+
+// prepare returns the input of the operation with the key of the
+// item. Auto update timestamps are set, unless the update already
+// sets their attributes. If the item is versioned, the version
+// condition is added and the version is incremented.
+func (q *UpdateItem) prepare() (*dynamodb.UpdateItemInput, *version, *timestamps, error) {
+	input := *q.input
 	var err error
-	q.input.Key, err = q.dynago.key(q.item)
+	input.Key, err = q.dynago.key(q.item)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("q.dynago.key: %w", err)
+	}
+	ts, err := q.dynago.autoTimestamps(q.item, false)
 	if err != nil {
-		return fmt.Errorf("q.dynago.key: %w", err)
+		return nil, nil, nil, err
 	}
-	_, err = q.dynago.ddb.UpdateItem(q.input)
+	if auto := ts.update(); len(auto) > 0 {
+		set := updatedAttrs(input.UpdateExpression, input.ExpressionAttributeNames)
+		input.ExpressionAttributeNames = copyNames(input.ExpressionAttributeNames)
+		input.ExpressionAttributeValues = copyValues(input.ExpressionAttributeValues)
+		e := q.dynago.expression("a", nil, &input.ExpressionAttributeNames, &input.ExpressionAttributeValues)
+		var actions []string
+		for _, f := range auto {
+			names := append([]string{f.attrName}, f.attrsToCopy...)
+			if slices.Some(names, func(name string) bool { return set[name] }) {
+				ts.skip(f)
+				continue
+			}
+			av, err := f.attrVal(ts.val)
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("f.attrVal: %w", err)
+			}
+			ph := e.rawValue(av)
+			for _, name := range names {
+				actions = append(actions, e.name(name)+" = "+ph)
+			}
+		}
+		if len(actions) > 0 {
+			input.UpdateExpression = addSetAction(input.UpdateExpression, strings.Join(actions, ", "))
+		}
+	}
+	ver, err := q.dynago.version(q.item)
 	if err != nil {
-		return fmt.Errorf("d.ddb.UpdateItem: %w", err)
+		return nil, nil, nil, err
+	}
+	if ver != nil {
+		input.ExpressionAttributeNames = copyNames(input.ExpressionAttributeNames)
+		input.ExpressionAttributeValues = copyValues(input.ExpressionAttributeValues)
+		e := q.dynago.expression("v", nil, &input.ExpressionAttributeNames, &input.ExpressionAttributeValues)
+		input.ConditionExpression = andCondition(input.ConditionExpression, ver.condition(e))
+		input.UpdateExpression = addSetAction(input.UpdateExpression, e.name(ver.field.attrName)+" = "+e.rawValue(ver.next()))
 	}
-	return err
+	return &input, ver, ts, nil
 }
 
 // TransactionWriteItem implements the TransactionWriteItemer
@@ -92,19 +375,32 @@ func (q *UpdateItem) TransactionWriteItem() (*dynamodb.TransactWriteItem, error)
 	if q.err != nil {
 		return nil, q.err
 	}
-	var err error
-	q.input.Key, err = q.dynago.key(q.item)
+	input, ver, ts, err := q.prepare()
 	if err != nil {
-		return nil, fmt.Errorf("q.dynago.key: %w", err)
+		return nil, err
 	}
+	q.ver, q.ts = ver, ts
 	return &dynamodb.TransactWriteItem{
 		Update: &dynamodb.Update{
-			ConditionExpression:       q.input.ConditionExpression,
-			ExpressionAttributeNames:  q.input.ExpressionAttributeNames,
-			ExpressionAttributeValues: q.input.ExpressionAttributeValues,
-			Key:                       q.input.Key,
-			UpdateExpression:          q.input.UpdateExpression,
-			TableName:                 q.input.TableName,
+			ConditionExpression:       input.ConditionExpression,
+			ExpressionAttributeNames:  input.ExpressionAttributeNames,
+			ExpressionAttributeValues: input.ExpressionAttributeValues,
+			Key:                       input.Key,
+			UpdateExpression:          input.UpdateExpression,
+			TableName:                 input.TableName,
 		},
 	}, nil
 }
+
+func (q *UpdateItem) versioned() bool {
+	return q.ver != nil
+}
+
+func (q *UpdateItem) commit() {
+	if q.ts != nil {
+		q.ts.commit()
+	}
+	if q.ver != nil {
+		q.ver.commit()
+	}
+}
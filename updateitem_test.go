@@ -163,3 +163,110 @@ func TestUpdateItemDefaultTableName(t *testing.T) {
 	}
 	ddb.done()
 }
+
+func TestUpdateItemReturnConsumedCapacity(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		*CompositeTable
+		Name string `attr:"PK" fmt:"Person#{}"`
+	}
+	p := Person{Name: "foo"}
+	tableName := "bar"
+	cc := &dynamodb.ConsumedCapacity{TableName: &tableName, CapacityUnits: aws.Float64(1)}
+	ddb.MockUpdate(&dynamodb.UpdateItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Person#foo")},
+		},
+		TableName:              &tableName,
+		ReturnConsumedCapacity: aws.String(dynamodb.ReturnConsumedCapacityTotal),
+	}, &dynamodb.UpdateItemOutput{ConsumedCapacity: cc})
+	var output dynago.UpdateItemOutput
+	if err := client.UpdateItem(&p).
+		TableName(tableName).
+		ReturnConsumedCapacity(dynamodb.ReturnConsumedCapacityTotal).
+		Output(&output).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, cc, output.ConsumedCapacity)
+	ddb.done()
+}
+
+func TestUpdateItemReturnValues(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		*CompositeTable
+		Name  string `attr:"PK" fmt:"Person#{}"`
+		Count int64
+	}
+	p := Person{Name: "foo"}
+	tableName := "bar"
+	ddb.MockUpdate(&dynamodb.UpdateItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Person#foo")},
+		},
+		TableName:        &tableName,
+		UpdateExpression: aws.String("ADD #c :one"),
+		ExpressionAttributeNames: map[string]*string{
+			"#c": aws.String("Count"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":one": {N: aws.String("1")},
+		},
+		ReturnValues: aws.String(dynamodb.ReturnValueAllNew),
+	}, &dynamodb.UpdateItemOutput{
+		Attributes: map[string]*dynamodb.AttributeValue{
+			"PK":    {S: aws.String("Person#foo")},
+			"Count": {N: aws.String("7")},
+		},
+	})
+	if err := client.UpdateItem(&p).
+		TableName(tableName).
+		UpdateExpression("ADD #c :one").
+		ExpressionAttributeName("#c", "Count").
+		ExpressionAttributeValue(":one", 1).
+		ReturnValues(dynamodb.ReturnValueAllNew).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, Person{Name: "foo", Count: 7}, p)
+	ddb.done()
+}
+
+func TestUpdateItemReturnValuesDestination(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb)
+	type Person struct {
+		*CompositeTable
+		Name  string `attr:"PK" fmt:"Person#{}"`
+		Count int64
+	}
+	type Counter struct {
+		Count int64
+	}
+	p := Person{Name: "foo"}
+	tableName := "bar"
+	ddb.MockUpdate(&dynamodb.UpdateItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Person#foo")},
+		},
+		TableName:    &tableName,
+		ReturnValues: aws.String(dynamodb.ReturnValueUpdatedOld),
+	}, &dynamodb.UpdateItemOutput{
+		Attributes: map[string]*dynamodb.AttributeValue{
+			"Count": {N: aws.String("6")},
+		},
+	})
+	var old Counter
+	if err := client.UpdateItem(&p).
+		TableName(tableName).
+		ReturnValues(dynamodb.ReturnValueUpdatedOld, &old).
+		Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, Counter{Count: 6}, old)
+	assertEq(t, Person{Name: "foo"}, p)
+	ddb.done()
+}
@@ -1,16 +1,51 @@
 package dynago
 
 import (
+	"context"
 	"fmt"
+	"math/rand"
 	"reflect"
+	"sort"
 	"strconv"
-
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/twharmon/dynago/internal/tags"
+)
+
+// Layouts of time.Time values stored as the number of seconds,
+// milliseconds or nanoseconds since the Unix epoch. They can be used in
+// the layout tag or passed to ExpressionAttributeValue. Values are
+// stored as Numbers, or as their decimal string in fmt templates.
+const (
+	LayoutUnix      = tags.LayoutUnix
+	LayoutUnixMilli = tags.LayoutUnixMilli
+	LayoutUnixNano  = tags.LayoutUnixNano
 )
 
+// FormatTime formats t with the layout, which may be LayoutUnix,
+// LayoutUnixMilli or LayoutUnixNano. It is used by the methods
+// generated by cmd/dynago-gen.
+func FormatTime(t time.Time, layout string) string {
+	return tags.FormatTime(t, layout)
+}
+
+// ParseTime parses s with the layout, which may be LayoutUnix,
+// LayoutUnixMilli or LayoutUnixNano, in which case the time is in UTC.
+// It is used by the methods generated by cmd/dynago-gen.
+func ParseTime(s string, layout string) (time.Time, error) {
+	return tags.ParseTime(s, layout)
+}
+
+// errNumber returns the error of unmarshalling a Number into a value
+// of the type ty stored as a String, such as a time.Time whose layout
+// is not LayoutUnix, LayoutUnixMilli or LayoutUnixNano.
+func errNumber(ty reflect.Type, layout string) error {
+	return fmt.Errorf("dynago: type mismatch: can not unmarshal N into %s with layout %q", ty, layout)
+}
+
 func tyVal(v interface{}) (reflect.Type, reflect.Value) {
 	val := reflect.ValueOf(v)
 	for val.Kind() == reflect.Pointer {
@@ -19,25 +54,51 @@ func tyVal(v interface{}) (reflect.Type, reflect.Value) {
 	return val.Type(), val
 }
 
-func trimDelims(s string) string {
-	return s[1 : len(s)-1]
-}
-
 func (d *Dynago) simpleUnmarshal(v reflect.Value, av *dynamodb.AttributeValue, layout string) error {
 	if av == nil {
 		return nil
 	}
+	if av.NULL != nil && *av.NULL {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
 	for v.Kind() == reflect.Pointer {
 		if v.IsNil() {
 			v.Set(reflect.New(v.Type().Elem()))
 		}
 		v = v.Elem()
 	}
+	if ok, err := unmarshalCustom(v, av); ok {
+		return err
+	}
 	switch v.Kind() {
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			if av.B != nil {
+				reflect.Copy(v, reflect.ValueOf(av.B))
+			}
+			return nil
+		}
+		for i := 0; i < len(av.L) && i < v.Len(); i++ {
+			if err := d.simpleUnmarshal(v.Index(i), av.L[i], layout); err != nil {
+				return err
+			}
+		}
+	case reflect.Interface:
+		if v.NumMethod() != 0 {
+			return fmt.Errorf("dynago: can not unmarshal into %s", v.Type())
+		}
+		var i interface{}
+		if err := dynamodbattribute.Unmarshal(av, &i); err != nil {
+			return err
+		}
+		if i != nil {
+			v.Set(reflect.ValueOf(i))
+		}
 	case reflect.Slice:
 		if v.Type().Elem().Kind() == reflect.Uint8 {
 			if av.B != nil {
-				v.Set(reflect.ValueOf(av.B))
+				v.Set(reflect.ValueOf(av.B).Convert(v.Type()))
 			}
 		} else {
 			sl := reflect.MakeSlice(v.Type(), len(av.L), len(av.L))
@@ -50,13 +111,29 @@ func (d *Dynago) simpleUnmarshal(v reflect.Value, av *dynamodb.AttributeValue, l
 		}
 	case reflect.Struct:
 		if v.Type() == timeType {
-			ti, err := time.Parse(layout, *av.S)
+			var s *string
+			switch {
+			case av.N != nil:
+				if !tags.IsUnixLayout(layout) {
+					return errNumber(v.Type(), layout)
+				}
+				s = av.N
+			case av.S != nil:
+				s = av.S
+			default:
+				return nil
+			}
+			ti, err := tags.ParseTime(*s, layout)
 			if err != nil {
 				return err
 			}
 			v.Set(reflect.ValueOf(ti))
 		} else {
-			if err := d.Unmarshal(av.M, v.Addr().Interface()); err != nil {
+			c, err := d.codec(v.Type())
+			if err != nil {
+				return fmt.Errorf("d.codec: %w", err)
+			}
+			if err := c.unmarshal(av.M, v); err != nil {
 				return err
 			}
 		}
@@ -103,10 +180,35 @@ func (d *Dynago) simpleUnmarshal(v reflect.Value, av *dynamodb.AttributeValue, l
 }
 
 func (d *Dynago) simpleMarshal(v reflect.Value, layout string) (*dynamodb.AttributeValue, error) {
-	for v.Kind() == reflect.Pointer {
+	for {
+		if av, ok, err := marshalCustom(v); ok {
+			return av, err
+		}
+		if v.Kind() != reflect.Pointer && v.Kind() != reflect.Interface {
+			break
+		}
 		v = v.Elem()
 	}
 	switch v.Kind() {
+	case reflect.Invalid:
+		return nil, nil
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			val := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(val), v)
+			return &dynamodb.AttributeValue{B: val}, nil
+		}
+		av := &dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{}}
+		for i := 0; i < v.Len(); i++ {
+			item, err := d.simpleMarshal(v.Index(i), layout)
+			if err != nil {
+				return nil, fmt.Errorf("d.simpleMarshal: %w", err)
+			}
+			if item != nil {
+				av.L = append(av.L, item)
+			}
+		}
+		return av, nil
 	case reflect.Slice:
 		if v.Type().Elem().Kind() == reflect.Uint8 {
 			val := v.Bytes()
@@ -125,12 +227,22 @@ func (d *Dynago) simpleMarshal(v reflect.Value, layout string) (*dynamodb.Attrib
 		return av, nil
 	case reflect.Struct:
 		if v.Type() == timeType {
-			s := v.Interface().(time.Time).Format(layout)
+			s := tags.FormatTime(v.Interface().(time.Time), layout)
+			if tags.IsUnixLayout(layout) {
+				return &dynamodb.AttributeValue{N: &s}, nil
+			}
 			return &dynamodb.AttributeValue{S: &s}, nil
 		}
-		item, err := d.Marshal(v.Interface())
+		c, err := d.codec(v.Type())
 		if err != nil {
-			return nil, fmt.Errorf("d.Marshal: %w", err)
+			return nil, fmt.Errorf("d.codec: %w", err)
+		}
+		item, err := c.marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		if d.config.AdditionalAttrs != nil && v.Type().Implements(keyerType) {
+			d.config.AdditionalAttrs(item, v)
 		}
 		return &dynamodb.AttributeValue{M: item}, nil
 	case reflect.String:
@@ -152,10 +264,158 @@ func (d *Dynago) simpleMarshal(v reflect.Value, layout string) (*dynamodb.Attrib
 		av, err := dynamodbattribute.MarshalMap(v.Interface())
 		return &dynamodb.AttributeValue{M: av}, err
 	default:
-		return nil, nil
+		return nil, fmt.Errorf("dynago: can not marshal value of kind %s", v.Kind())
 	}
 }
 
 type Float interface {
 	float32 | float64
 }
+
+// keyString returns a string that uniquely identifies the given
+// primary key.
+func keyString(key map[string]*dynamodb.AttributeValue) string {
+	names := make([]string, 0, len(key))
+	for name := range key {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, name := range names {
+		av := key[name]
+		b.WriteString(name)
+		switch {
+		case av == nil:
+			b.WriteString("=NULL")
+		case av.S != nil:
+			b.WriteString("=S")
+			b.WriteString(*av.S)
+		case av.N != nil:
+			b.WriteString("=N")
+			b.WriteString(*av.N)
+		default:
+			b.WriteString("=B")
+			b.Write(av.B)
+		}
+		b.WriteByte(0)
+	}
+	return b.String()
+}
+
+// pick returns the attributes of the item with the given names.
+func pick(item map[string]*dynamodb.AttributeValue, names []string) map[string]*dynamodb.AttributeValue {
+	m := make(map[string]*dynamodb.AttributeValue, len(names))
+	for _, name := range names {
+		m[name] = item[name]
+	}
+	return m
+}
+
+// batchBackoff sleeps before the given retry attempt of a batch
+// operation, using exponential backoff with full jitter. The context's
+// error is returned if it is done before the sleep is over.
+func batchBackoff(ctx context.Context, attempt int) error {
+	d := batchBackoffBase << attempt
+	if d <= 0 || d > batchBackoffMax {
+		d = batchBackoffMax
+	}
+	t := time.NewTimer(time.Duration(rand.Int63n(int64(d))))
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+const (
+	batchBackoffBase = 25 * time.Millisecond
+	batchBackoffMax  = 2 * time.Second
+)
+
+// itemSlice is the slice that items of a Query or Scan operation are
+// unmarshalled into.
+type itemSlice struct {
+	val      reflect.Value
+	elemType reflect.Type
+	indirect bool
+}
+
+func newItemSlice(items interface{}, op string) (*itemSlice, error) {
+	rv := reflect.ValueOf(items)
+	if rv.Kind() != reflect.Pointer {
+		return nil, fmt.Errorf("dynago: dynago.%s.Exec: v must be pointer", op)
+	}
+	for rv.Kind() == reflect.Pointer {
+		rv = reflect.Indirect(rv)
+	}
+	ft := rv.Type().Elem()
+	indirect := true
+	if ft.Kind() == reflect.Pointer {
+		ft = ft.Elem()
+		indirect = false
+	}
+	if ft.Kind() == reflect.Pointer {
+		return nil, fmt.Errorf("dynago: dynago.%s.Exec: elements of v can not be pointers to pointers", op)
+	}
+	return &itemSlice{val: rv, elemType: ft, indirect: indirect}, nil
+}
+
+// unmarshal unmarshals the given item into a new element of the
+// slice.
+func (s *itemSlice) unmarshal(d *Dynago, item map[string]*dynamodb.AttributeValue) (reflect.Value, error) {
+	iv := reflect.New(s.elemType)
+	if err := d.Unmarshal(item, iv.Interface()); err != nil {
+		return iv, fmt.Errorf("d.Unmarshal: %w", err)
+	}
+	if s.indirect {
+		iv = reflect.Indirect(iv)
+	}
+	return iv, nil
+}
+
+// set replaces the slice with the given items.
+func (s *itemSlice) set(d *Dynago, items []map[string]*dynamodb.AttributeValue) error {
+	s.val.Set(reflect.MakeSlice(s.val.Type(), 0, len(items)))
+	return s.append(d, items)
+}
+
+// append appends the given items to the slice.
+func (s *itemSlice) append(d *Dynago, items []map[string]*dynamodb.AttributeValue) error {
+	sl := s.val
+	for _, item := range items {
+		iv, err := s.unmarshal(d, item)
+		if err != nil {
+			return err
+		}
+		sl = reflect.Append(sl, iv)
+	}
+	s.val.Set(sl)
+	return nil
+}
+
+// addConsumedCapacity adds the capacity units consumed by src to dst.
+func addConsumedCapacity(dst **dynamodb.ConsumedCapacity, src *dynamodb.ConsumedCapacity) {
+	if src == nil {
+		return
+	}
+	if *dst == nil {
+		cc := *src
+		*dst = &cc
+		return
+	}
+	add := func(a **float64, b *float64) {
+		if b == nil {
+			return
+		}
+		sum := *b
+		if *a != nil {
+			sum += **a
+		}
+		*a = &sum
+	}
+	add(&(*dst).CapacityUnits, src.CapacityUnits)
+	add(&(*dst).ReadCapacityUnits, src.ReadCapacityUnits)
+	add(&(*dst).WriteCapacityUnits, src.WriteCapacityUnits)
+}
@@ -0,0 +1,178 @@
+package dynago
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// versionConflictError is returned when the condition of a versioned
+// write fails. It matches ErrVersionConflict with errors.Is, as well
+// as the error it wraps.
+type versionConflictError struct {
+	err error
+}
+
+// Error implements the error interface.
+func (e *versionConflictError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrVersionConflict, e.err)
+}
+
+// Unwrap returns the wrapped error.
+func (e *versionConflictError) Unwrap() error {
+	return e.err
+}
+
+// Is reports whether the target is ErrVersionConflict.
+func (e *versionConflictError) Is(target error) bool {
+	return target == ErrVersionConflict
+}
+
+// versioner is implemented by operations that may write a versioned
+// item. commit is called once a transaction the operation is part of
+// succeeds, to set the new version and auto timestamps on the item.
+type versioner interface {
+	versioned() bool
+	commit()
+}
+
+// version is the version of an item written with optimistic locking.
+type version struct {
+	field   *field
+	val     reflect.Value
+	current uint64
+}
+
+// version returns the version of the item, or nil if the item has no
+// field with the version tag.
+func (d *Dynago) version(item Keyer) (*version, error) {
+	ty, val := tyVal(item)
+	c, err := d.codec(ty)
+	if err != nil {
+		return nil, fmt.Errorf("d.codec: %w", err)
+	}
+	f := c.version
+	if f == nil {
+		return nil, nil
+	}
+	v := version{field: f, val: f.value(val, false)}
+	fv := v.val
+	for fv.Kind() == reflect.Pointer && !fv.IsNil() {
+		fv = fv.Elem()
+	}
+	switch fv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if fv.Int() < 0 {
+			return nil, fmt.Errorf("dynago: version %s must not be negative", f.attrName)
+		}
+		v.current = uint64(fv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.current = fv.Uint()
+	}
+	return &v, nil
+}
+
+// condition returns a condition that the version of the item in
+// DynamoDB is the current version, or that the item has no version if
+// the current version is 0.
+func (v *version) condition(e *expression) string {
+	name := e.name(v.field.attrName)
+	if v.current == 0 {
+		return "attribute_not_exists(" + name + ")"
+	}
+	cur := strconv.FormatUint(v.current, 10)
+	return name + " = " + e.rawValue(&dynamodb.AttributeValue{N: &cur})
+}
+
+// next returns the attribute value of the next version.
+func (v *version) next() *dynamodb.AttributeValue {
+	next := strconv.FormatUint(v.current+1, 10)
+	return &dynamodb.AttributeValue{N: &next}
+}
+
+// setNext sets the attribute of the next version and its copies in
+// the item.
+func (v *version) setNext(item map[string]*dynamodb.AttributeValue) {
+	next := v.next()
+	item[v.field.attrName] = next
+	for _, cp := range v.field.attrsToCopy {
+		item[cp] = next
+	}
+}
+
+// commit sets the version field of the item to the next version. It
+// is called once the write succeeds. Nothing is done if the item was
+// not passed by pointer.
+func (v *version) commit() {
+	fv := v.val
+	if !fv.CanSet() {
+		return
+	}
+	for fv.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		fv = fv.Elem()
+	}
+	switch fv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		fv.SetInt(int64(v.current + 1))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		fv.SetUint(v.current + 1)
+	}
+}
+
+// versionError wraps a conditional check failure of a versioned write
+// so that it matches ErrVersionConflict.
+func versionError(err error, v *version) error {
+	if v != nil && errors.Is(err, ErrConditionalCheckFailed) {
+		return &versionConflictError{err: err}
+	}
+	return err
+}
+
+// returnsOld reports whether the return values rv are attributes of
+// the item as they were before the write. If they are unmarshalled into
+// the item, the new version and auto timestamps are not committed onto
+// it, since they do not belong to the old attributes.
+func returnsOld(rv *string) bool {
+	return rv != nil && (*rv == dynamodb.ReturnValueAllOld || *rv == dynamodb.ReturnValueUpdatedOld)
+}
+
+// andCondition combines an existing condition expression with cond.
+func andCondition(exp *string, cond string) *string {
+	if exp == nil || *exp == "" {
+		return &cond
+	}
+	s := "(" + *exp + ") AND (" + cond + ")"
+	return &s
+}
+
+// copyNames returns a copy of names, so that placeholders can be added
+// to it without changing the input of the operation.
+func copyNames(names map[string]*string) map[string]*string {
+	if names == nil {
+		return nil
+	}
+	m := make(map[string]*string, len(names))
+	for k, v := range names {
+		m[k] = v
+	}
+	return m
+}
+
+// copyValues returns a copy of values, so that placeholders can be
+// added to it without changing the input of the operation.
+func copyValues(values map[string]*dynamodb.AttributeValue) map[string]*dynamodb.AttributeValue {
+	if values == nil {
+		return nil
+	}
+	m := make(map[string]*dynamodb.AttributeValue, len(values))
+	for k, v := range values {
+		m[k] = v
+	}
+	return m
+}
@@ -0,0 +1,290 @@
+package dynago_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/twharmon/dynago"
+)
+
+type VersionedPerson struct {
+	*SimpleTable
+	Name    string `attr:"PK" fmt:"Person#{}"`
+	Version int64  `version:""`
+}
+
+func TestVersionPutItemNew(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	p := VersionedPerson{Name: "foo"}
+	ddb.MockPut(&dynamodb.PutItemInput{
+		TableName: aws.String("foo"),
+		Item: map[string]*dynamodb.AttributeValue{
+			"PK":      {S: aws.String("Person#foo")},
+			"Version": {N: aws.String("1")},
+		},
+		ConditionExpression: aws.String("attribute_not_exists(#v0)"),
+		ExpressionAttributeNames: map[string]*string{
+			"#v0": aws.String("Version"),
+		},
+	})
+	if err := client.PutItem(&p).Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, int64(1), p.Version)
+	ddb.done()
+}
+
+func TestVersionPutItemConflict(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	p := VersionedPerson{Name: "foo", Version: 3}
+	ddb.MockPutError(&dynamodb.PutItemInput{
+		TableName: aws.String("foo"),
+		Item: map[string]*dynamodb.AttributeValue{
+			"PK":      {S: aws.String("Person#foo")},
+			"Version": {N: aws.String("4")},
+		},
+		ConditionExpression: aws.String("(attribute_exists(#c0)) AND (#v0 = :v0)"),
+		ExpressionAttributeNames: map[string]*string{
+			"#c0": aws.String("PK"),
+			"#v0": aws.String("Version"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":v0": {N: aws.String("3")},
+		},
+	}, awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "", nil))
+	err := client.PutItem(&p).Condition(dynago.Attr("PK").Exists()).Exec()
+	if !errors.Is(err, dynago.ErrVersionConflict) {
+		t.Fatalf("want ErrVersionConflict; got %v", err)
+	}
+	if !errors.Is(err, dynago.ErrConditionalCheckFailed) {
+		t.Fatalf("want ErrConditionalCheckFailed; got %v", err)
+	}
+	assertEq(t, int64(3), p.Version)
+	ddb.done()
+}
+
+func TestVersionUpdateItem(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	p := VersionedPerson{Name: "foo", Version: 2}
+	ddb.MockUpdate(&dynamodb.UpdateItemInput{
+		TableName: aws.String("foo"),
+		Key: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Person#foo")},
+		},
+		UpdateExpression:    aws.String("SET #v0 = :v1, #u0 = :u0"),
+		ConditionExpression: aws.String("#v0 = :v0"),
+		ExpressionAttributeNames: map[string]*string{
+			"#u0": aws.String("Age"),
+			"#v0": aws.String("Version"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":u0": {N: aws.String("33")},
+			":v0": {N: aws.String("2")},
+			":v1": {N: aws.String("3")},
+		},
+	})
+	q := client.UpdateItem(&p).Set("Age", 33)
+	if err := q.Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, int64(3), p.Version)
+	ddb.done()
+}
+
+func TestVersionPutItemReturnOld(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	p := VersionedPerson{Name: "foo", Version: 3}
+	ddb.MockPut(&dynamodb.PutItemInput{
+		TableName: aws.String("foo"),
+		Item: map[string]*dynamodb.AttributeValue{
+			"PK":      {S: aws.String("Person#foo")},
+			"Version": {N: aws.String("4")},
+		},
+		ConditionExpression: aws.String("#v0 = :v0"),
+		ExpressionAttributeNames: map[string]*string{
+			"#v0": aws.String("Version"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":v0": {N: aws.String("3")},
+		},
+		ReturnValues: aws.String(dynamodb.ReturnValueAllOld),
+	}, &dynamodb.PutItemOutput{
+		Attributes: map[string]*dynamodb.AttributeValue{
+			"PK":      {S: aws.String("Person#foo")},
+			"Version": {N: aws.String("3")},
+		},
+	})
+	if err := client.PutItem(&p).ReturnValues(dynamodb.ReturnValueAllOld).Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, int64(3), p.Version)
+	ddb.done()
+}
+
+func TestVersionUpdateItemReturnUpdatedOld(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	p := VersionedPerson{Name: "foo", Version: 2}
+	ddb.MockUpdate(&dynamodb.UpdateItemInput{
+		TableName: aws.String("foo"),
+		Key: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Person#foo")},
+		},
+		UpdateExpression:    aws.String("SET #v0 = :v1, #u0 = :u0"),
+		ConditionExpression: aws.String("#v0 = :v0"),
+		ExpressionAttributeNames: map[string]*string{
+			"#u0": aws.String("Age"),
+			"#v0": aws.String("Version"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":u0": {N: aws.String("33")},
+			":v0": {N: aws.String("2")},
+			":v1": {N: aws.String("3")},
+		},
+		ReturnValues: aws.String(dynamodb.ReturnValueUpdatedOld),
+	}, &dynamodb.UpdateItemOutput{
+		Attributes: map[string]*dynamodb.AttributeValue{
+			"Version": {N: aws.String("2")},
+		},
+	})
+	q := client.UpdateItem(&p).Set("Age", 33).ReturnValues(dynamodb.ReturnValueUpdatedOld)
+	if err := q.Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, int64(2), p.Version)
+	ddb.done()
+}
+
+func TestVersionUpdateItemRawExpression(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	p := VersionedPerson{Name: "foo", Version: 2}
+	ddb.MockUpdate(&dynamodb.UpdateItemInput{
+		TableName: aws.String("foo"),
+		Key: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Person#foo")},
+		},
+		UpdateExpression:    aws.String("SET #v0 = :v1 REMOVE Age"),
+		ConditionExpression: aws.String("#v0 = :v0"),
+		ExpressionAttributeNames: map[string]*string{
+			"#v0": aws.String("Version"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":v0": {N: aws.String("2")},
+			":v1": {N: aws.String("3")},
+		},
+	})
+	if err := client.UpdateItem(&p).UpdateExpression("REMOVE Age").Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	ddb.done()
+}
+
+func TestVersionDeleteItem(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	p := VersionedPerson{Name: "foo", Version: 5}
+	ddb.MockDelete(&dynamodb.DeleteItemInput{
+		TableName: aws.String("foo"),
+		Key: map[string]*dynamodb.AttributeValue{
+			"PK": {S: aws.String("Person#foo")},
+		},
+		ConditionExpression: aws.String("#v0 = :v0"),
+		ExpressionAttributeNames: map[string]*string{
+			"#v0": aws.String("Version"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":v0": {N: aws.String("5")},
+		},
+	})
+	if err := client.DeleteItem(&p).Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, int64(5), p.Version)
+	ddb.done()
+}
+
+func TestVersionTransaction(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	foo := VersionedPerson{Name: "foo", Version: 1}
+	bar := VersionedPerson{Name: "bar"}
+	input := &dynamodb.TransactWriteItemsInput{
+		TransactItems: []*dynamodb.TransactWriteItem{
+			{
+				Put: &dynamodb.Put{
+					TableName: aws.String("foo"),
+					Item: map[string]*dynamodb.AttributeValue{
+						"PK":      {S: aws.String("Person#foo")},
+						"Version": {N: aws.String("2")},
+					},
+					ConditionExpression: aws.String("#v0 = :v0"),
+					ExpressionAttributeNames: map[string]*string{
+						"#v0": aws.String("Version"),
+					},
+					ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+						":v0": {N: aws.String("1")},
+					},
+				},
+			},
+			{
+				Update: &dynamodb.Update{
+					TableName: aws.String("foo"),
+					Key: map[string]*dynamodb.AttributeValue{
+						"PK": {S: aws.String("Person#bar")},
+					},
+					UpdateExpression:    aws.String("SET #v0 = :v0"),
+					ConditionExpression: aws.String("attribute_not_exists(#v0)"),
+					ExpressionAttributeNames: map[string]*string{
+						"#v0": aws.String("Version"),
+					},
+					ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+						":v0": {N: aws.String("1")},
+					},
+				},
+			},
+		},
+	}
+	ddb.MockTransactWriteItemsError(input, &dynamodb.TransactionCanceledException{
+		CancellationReasons: []*dynamodb.CancellationReason{
+			{Code: aws.String("ConditionalCheckFailed")},
+			{Code: aws.String("None")},
+		},
+	})
+	tx := client.TransactionWriteItems().Items(client.PutItem(&foo), client.UpdateItem(&bar))
+	if err := tx.Exec(); !errors.Is(err, dynago.ErrVersionConflict) {
+		t.Fatalf("want ErrVersionConflict; got %v", err)
+	}
+	assertEq(t, int64(1), foo.Version)
+	assertEq(t, int64(0), bar.Version)
+	ddb.done()
+
+	ddb.MockTransactWriteItems(input)
+	if err := tx.Exec(); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	assertEq(t, int64(2), foo.Version)
+	assertEq(t, int64(1), bar.Version)
+	ddb.done()
+}
+
+func TestVersionTagNotInteger(t *testing.T) {
+	ddb := mock(t)
+	client := dynago.New(ddb, &dynago.Config{DefaultTableName: "foo"})
+	type Person struct {
+		*SimpleTable
+		Name    string `attr:"PK" fmt:"Person#{}"`
+		Version string `version:""`
+	}
+	if err := client.PutItem(&Person{Name: "foo"}).Exec(); err == nil {
+		t.Fatalf("want err")
+	}
+	ddb.done()
+}